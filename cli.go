@@ -0,0 +1,656 @@
+// ================================================================
+// user-service/cli.go - Operational subcommands
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/database"
+	"gitlab.com/nodiviti/user-service/handlers"
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+// Exit codes of the subcommands
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command is an operational subcommand. Results are written to stdout as
+// JSON; logs and errors go to stderr.
+type command struct {
+	usage string
+	run   func(cfg *config.Config, args []string) (interface{}, error)
+}
+
+var commands = map[string]command{
+	"serve":          {"serve", nil},
+	"migrate":        {"migrate", runMigrate},
+	"create-admin":   {"create-admin --username NAME --email EMAIL (--password-file FILE | --password-stdin) [--full-name NAME]", runCreateAdmin},
+	"reset-password": {"reset-password <id|username|email>", runResetPassword},
+	"import":         {"import [--dry-run] <file.json|file.csv>", runImport},
+	"export":         {"export [--format json|csv] [--output FILE] [--role ROLE] [--class-level CLASS] [--include-inactive]", runExport},
+	"deactivate":     {"deactivate <id|username|email>", runDeactivate},
+	"promote-year":   {"promote-year --from YEAR --to YEAR [--final-grade N] [--map FILE] [--graduation-date YYYY-MM-DD] [--dry-run]", runPromoteYear},
+	"check-config":   {"check-config [--offline]", runCheckConfig},
+}
+
+// configProblems holds the validation problems runCommand found while loading
+// the configuration, for check-config to report
+var configProblems []string
+
+// usageError marks an error caused by bad arguments
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+// runCommand dispatches a subcommand and returns the process exit code.
+// Without arguments the HTTP server is started.
+func runCommand(args []string) int {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return exitOK
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		return exitUsage
+	}
+
+	// Every configuration problem is listed at once; check-config reports
+	// them itself, next to its other checks
+	cfg, err := config.Load()
+	var invalid *config.ValidationError
+	if err != nil && !(name == "check-config" && errors.As(err, &invalid)) {
+		body := map[string]interface{}{"error": "invalid configuration"}
+		if errors.As(err, &invalid) {
+			body["problems"] = invalid.Problems
+		} else {
+			body["error"] = err.Error()
+		}
+		writeJSON(os.Stderr, body)
+		return exitFailure
+	}
+	if invalid != nil {
+		configProblems = invalid.Problems
+	}
+
+	if name == "serve" {
+		serve(cfg)
+		return exitOK
+	}
+
+	// Logs go to stderr so stdout only carries the command's JSON result.
+	// Unless LOG_LEVEL asks for more, only warnings and errors are shown.
+	logCfg := cfg.Logging
+	if os.Getenv("LOG_LEVEL") == "" {
+		logCfg.Level = "warn"
+	}
+	if _, err := logging.Setup(logCfg, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		return exitFailure
+	}
+
+	result, err := cmd.run(cfg, args)
+	if err != nil {
+		body := map[string]interface{}{"error": err.Error()}
+		if domainErr, ok := services.AsError(err); ok {
+			body["code"] = domainErr.Code
+			if len(domainErr.Fields) > 0 {
+				body["fields"] = domainErr.Fields
+			}
+		}
+		writeJSON(os.Stderr, body)
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(os.Stderr, "usage: user-service %s\n", cmd.usage)
+			return exitUsage
+		}
+		if result != nil {
+			// Commands that fail with a report (import, check-config) still print it
+			writeJSON(os.Stdout, result)
+		}
+		return exitFailure
+	}
+
+	if result != nil {
+		writeJSON(os.Stdout, result)
+	}
+	return exitOK
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: user-service <command> [arguments]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "commands:")
+	for _, name := range []string{"serve", "migrate", "create-admin", "reset-password", "import", "export", "deactivate", "promote-year", "check-config"} {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// parseFlags parses command flags, turning flag errors into usage errors
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	return nil
+}
+
+// openDatabase connects to the database for a subcommand
+func openDatabase(cfg *config.Config) error {
+	if err := configurePasswords(cfg); err != nil {
+		return fmt.Errorf("invalid password configuration: %v", err)
+	}
+	return database.InitDatabase(cfg)
+}
+
+func runMigrate(cfg *config.Config, args []string) (interface{}, error) {
+	if err := parseFlags(flag.NewFlagSet("migrate", flag.ContinueOnError), args); err != nil {
+		return nil, err
+	}
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	if err := database.AutoMigrate(); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"migrated": true}, nil
+}
+
+func runCreateAdmin(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := fs.String("username", "", "admin username")
+	email := fs.String("email", "", "admin email")
+	fullName := fs.String("full-name", "", "admin full name")
+	passwordFile := fs.String("password-file", "", "file holding the password")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if *username == "" || *email == "" {
+		return nil, &usageError{"--username and --email are required"}
+	}
+
+	// Passwords are never taken from argv, where other users could see them
+	var password string
+	switch {
+	case *passwordFile != "" && *passwordStdin:
+		return nil, &usageError{"use only one of --password-file and --password-stdin"}
+	case *passwordFile != "":
+		data, err := os.ReadFile(*passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password file: %v", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	case *passwordStdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password: %v", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	default:
+		return nil, &usageError{"--password-file or --password-stdin is required"}
+	}
+
+	req := &models.CreateUserRequest{
+		Username: *username,
+		Email:    *email,
+		Password: password,
+		Role:     "admin",
+	}
+	if *fullName != "" {
+		req.FullName = fullName
+	}
+	if err := handlers.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	user, err := services.NewUserService().CreateUser(req)
+	if err != nil {
+		return nil, err
+	}
+	return user.ToResponse().In(cfg.Location()), nil
+}
+
+func runResetPassword(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, &usageError{"exactly one user is required"}
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	userService := services.NewUserService()
+	user, err := userService.FindUser(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	temporaryPassword, err := userService.ResetPassword(user.ID, cfg.Password.TemporaryLength, cfg.Password.HistorySize)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":                   user.ID,
+		"username":             user.Username,
+		"temporary_password":   temporaryPassword,
+		"must_change_password": true,
+	}, nil
+}
+
+func runDeactivate(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("deactivate", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, &usageError{"exactly one user is required"}
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	userService := services.NewUserService()
+	user, err := userService.FindUser(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userService.DeactivateUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":        user.ID,
+		"username":  user.Username,
+		"is_active": false,
+	}, nil
+}
+
+func runImport(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only validate the file")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, &usageError{"exactly one file is required"}
+	}
+
+	rows, err := readImportFile(fs.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	report, err := services.NewUserService().ImportUsers(rows, handlers.ValidateStruct, *dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%d of %d rows failed validation, no users were created", report.Failed, report.Total)
+	}
+	return report, nil
+}
+
+// readImportFile reads users from a JSON file (an array or {"users": [...]})
+// or a CSV file whose header row uses the JSON field names
+func readImportFile(path string) ([]models.CreateUserRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return parseImportCSV(data)
+	}
+
+	var rows []models.CreateUserRequest
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapped struct {
+			Users []models.CreateUserRequest `json:"users"`
+		}
+		err = json.Unmarshal(trimmed, &wrapped)
+		rows = wrapped.Users
+	} else {
+		err = json.Unmarshal(trimmed, &rows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %v", err)
+	}
+	return rows, nil
+}
+
+// parseImportCSV converts CSV rows to requests through JSON, so columns
+// follow the same names and types as the HTTP API. Empty cells are omitted.
+func parseImportCSV(data []byte) ([]models.CreateUserRequest, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	rows := make([]models.CreateUserRequest, 0, len(records)-1)
+	for i, record := range records[1:] {
+		object := make(map[string]string)
+		for j, value := range record {
+			if j < len(header) && value != "" {
+				object[strings.TrimSpace(header[j])] = value
+			}
+		}
+
+		encoded, _ := json.Marshal(object)
+		var row models.CreateUserRequest
+		if err := json.Unmarshal(encoded, &row); err != nil {
+			return nil, fmt.Errorf("invalid import file: row %d: %v", i+1, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func runExport(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "json", "json or csv")
+	output := fs.String("output", "", "write to FILE instead of stdout")
+	role := fs.String("role", "", "only export this role")
+	classLevel := fs.String("class-level", "", "only export this class")
+	includeInactive := fs.Bool("include-inactive", false, "include inactive users")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if *format != "json" && *format != "csv" {
+		return nil, &usageError{"--format must be json or csv"}
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	filter := services.UserFilter{Role: *role, ClassLevel: *classLevel, IncludeInactive: *includeInactive}
+	sort := []services.SortField{{Key: "id"}}
+	userService := services.NewUserService()
+
+	var users []*models.UserResponse
+	var cursor *services.Cursor
+	for {
+		page, err := userService.GetUsersByCursor(filter, sort, cursor, 500)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.Users {
+			users = append(users, page.Users[i].ToResponse().In(cfg.Location()))
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if cursor, err = services.DecodeCursor(page.NextCursor); err != nil {
+			return nil, err
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "csv" {
+		if err := writeUsersCSV(w, users); err != nil {
+			return nil, err
+		}
+	} else if *output != "" {
+		writeJSON(w, users)
+	} else {
+		return users, nil
+	}
+
+	if *output != "" {
+		return map[string]interface{}{"exported": len(users), "format": *format, "output": *output}, nil
+	}
+	return nil, nil
+}
+
+// exportColumns are the CSV columns of an export, named like the JSON fields
+var exportColumns = []string{
+	"id", "username", "email", "role", "full_name", "phone", "gender",
+	"employee_id", "student_id", "nisn", "class_level", "academic_year",
+	"status", "is_active", "created_at",
+}
+
+func writeUsersCSV(w io.Writer, users []*models.UserResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		fields := user.Fields(exportColumns)
+		record := make([]string, len(exportColumns))
+		for i, column := range exportColumns {
+			record[i] = csvValue(fields[column])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvValue formats a response field for CSV, leaving nil values empty
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func runPromoteYear(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("promote-year", flag.ContinueOnError)
+	from := fs.String("from", "", "academic year of the students to promote (required)")
+	to := fs.String("to", "", "academic year for promoted students (required)")
+	finalGrade := fs.Int("final-grade", 12, "students in this grade graduate")
+	mapFile := fs.String("map", "", "JSON file mapping classes, e.g. {\"Tahfidz A\": \"Tahfidz B\"}; an empty target graduates")
+	graduationDate := fs.String("graduation-date", "", "graduation date recorded for graduates (default today)")
+	dryRun := fs.Bool("dry-run", false, "only report the planned promotion")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	// Both years are required so that a repeated run promotes nobody twice
+	if *from == "" || *to == "" {
+		return nil, &usageError{"--from and --to are required"}
+	}
+
+	opts := services.PromotionOptions{FromYear: *from, ToYear: *to, FinalGrade: *finalGrade, DryRun: *dryRun}
+	opts.GraduatedOn = models.DateOf(time.Now().In(cfg.Location()))
+	if *graduationDate != "" {
+		date, err := models.ParseDate(*graduationDate)
+		if err != nil {
+			return nil, &usageError{"--graduation-date must be YYYY-MM-DD"}
+		}
+		opts.GraduatedOn = date
+	}
+	if *mapFile != "" {
+		data, err := os.ReadFile(*mapFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &opts.Classes); err != nil {
+			return nil, fmt.Errorf("invalid class map: %v", err)
+		}
+	}
+
+	if err := openDatabase(cfg); err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	return services.NewUserService().PromoteYear(opts)
+}
+
+// configCheck is one line of the check-config report
+type configCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // ok, warning or error
+	Message string `json:"message,omitempty"`
+}
+
+func runCheckConfig(cfg *config.Config, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("check-config", flag.ContinueOnError)
+	offline := fs.Bool("offline", false, "skip the database checks")
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+
+	var checks []configCheck
+	check := func(name string, err error) bool {
+		if err != nil {
+			checks = append(checks, configCheck{Name: name, Status: "error", Message: err.Error()})
+			return false
+		}
+		checks = append(checks, configCheck{Name: name, Status: "ok"})
+		return true
+	}
+
+	// Everything config.Load validated, including values that did not parse
+	for _, problem := range configProblems {
+		checks = append(checks, configCheck{Name: "config", Status: "error", Message: problem})
+	}
+	if len(configProblems) == 0 {
+		check("config", nil)
+	}
+	check("password_hashing", configurePasswords(cfg))
+	if cfg.Bootstrap.AdminPasswordFile != "" {
+		_, err := os.ReadFile(cfg.Bootstrap.AdminPasswordFile)
+		check("bootstrap_password_file", err)
+	}
+	if _, err := os.Stat(cfg.Upload.Path); errors.Is(err, os.ErrNotExist) {
+		checks = append(checks, configCheck{Name: "upload_path", Status: "warning", Message: fmt.Sprintf("%s does not exist, serve will create it", cfg.Upload.Path)})
+	} else {
+		check("upload_path", checkUploadDir(cfg.Upload.Path))
+	}
+
+	if !*offline && check("database", database.InitDatabase(cfg)) {
+		defer database.Close()
+
+		accounts, err := services.NewBootstrapService(services.NewUserService()).FindDefaultPasswordAccounts()
+		switch {
+		case err != nil:
+			check("default_passwords", err)
+		case len(accounts) > 0 && cfg.GinMode == "release":
+			check("default_passwords", fmt.Errorf("accounts %v still use the default password", accounts))
+		case len(accounts) > 0:
+			checks = append(checks, configCheck{Name: "default_passwords", Status: "warning", Message: fmt.Sprintf("accounts %v still use the default password", accounts)})
+		default:
+			check("default_passwords", nil)
+		}
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == "error" {
+			failed++
+		}
+	}
+
+	report := map[string]interface{}{"ok": failed == 0, "checks": checks}
+	if failed > 0 {
+		return report, fmt.Errorf("%d configuration checks failed", failed)
+	}
+	return report, nil
+}
+
+// checkUploadDir reports whether dir is a directory files can be written to,
+// probing with a temporary file that is removed again
+func checkUploadDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	file, err := os.CreateTemp(dir, ".check-config-*")
+	if err != nil {
+		return fmt.Errorf("upload directory not writable: %v", err)
+	}
+	name := file.Name()
+	file.Close()
+	return os.Remove(name)
+}
@@ -1,107 +1,592 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Config is read from defaults, then the optional CONFIG_FILE (YAML or TOML,
+// with the yaml/toml keys below), then environment variables, which win.
 type Config struct {
-	Port        string
-	GinMode     string
-	ServiceName string
-	Version     string
+	Port        string `yaml:"port" toml:"port"`
+	GinMode     string `yaml:"gin_mode" toml:"gin_mode"`
+	ServiceName string `yaml:"service_name" toml:"service_name"`
+	Version     string `yaml:"version" toml:"version"`
+	Timezone    string `yaml:"timezone" toml:"timezone"` // Institution's IANA timezone; timestamps are stored in UTC and shown in this zone by default
 
-	Database    DatabaseConfig
-	Redis       RedisConfig
-	AuthService AuthServiceConfig
-	Upload      UploadConfig
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Redis       RedisConfig       `yaml:"redis" toml:"redis"`
+	AuthService AuthServiceConfig `yaml:"auth_service" toml:"auth_service"`
+	Upload      UploadConfig      `yaml:"upload" toml:"upload"`
+	Lifecycle   LifecycleConfig   `yaml:"lifecycle" toml:"lifecycle"`
+	Password    PasswordConfig    `yaml:"password" toml:"password"`
+	Bootstrap   BootstrapConfig   `yaml:"bootstrap" toml:"bootstrap"`
+	Metrics     MetricsConfig     `yaml:"metrics" toml:"metrics"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	Logging     LoggingConfig     `yaml:"logging" toml:"logging"`
+	Health      HealthConfig      `yaml:"health" toml:"health"`
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	CORS        CORSConfig        `yaml:"cors" toml:"cors"`
+	Security    SecurityConfig    `yaml:"security" toml:"security"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-	SSLMode  string
+	Host         string `yaml:"host" toml:"host"`
+	Port         string `yaml:"port" toml:"port"`
+	User         string `yaml:"user" toml:"user"`
+	Password     string `yaml:"password" toml:"password"`
+	PasswordFile string `yaml:"password_file" toml:"password_file"` // Secret file, read into Password at load
+	Name         string `yaml:"name" toml:"name"`
+	SSLMode      string `yaml:"ssl_mode" toml:"ssl_mode"`
 }
 
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Enabled      bool   `yaml:"enabled" toml:"enabled"` // Only used, and checked by /readyz, when enabled
+	Host         string `yaml:"host" toml:"host"`
+	Port         string `yaml:"port" toml:"port"`
+	Password     string `yaml:"password" toml:"password"`
+	PasswordFile string `yaml:"password_file" toml:"password_file"` // Secret file, read into Password at load
+	DB           int    `yaml:"db" toml:"db"`
 }
 
 type AuthServiceConfig struct {
-	URL     string
-	Timeout time.Duration
+	URL     string        `yaml:"url" toml:"url"`
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
 }
 
 type UploadConfig struct {
-	Path             string
-	MaxSize          int64
-	AllowedFileTypes []string
+	Path             string   `yaml:"path" toml:"path"`
+	MaxSize          int64    `yaml:"max_size" toml:"max_size"`
+	AllowedFileTypes []string `yaml:"allowed_file_types" toml:"allowed_file_types"` // Extensions without the dot
 }
 
-func Load() *Config {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
-	}
+type LifecycleConfig struct {
+	TrashRetention time.Duration `yaml:"trash_retention" toml:"trash_retention"` // How long deleted users stay restorable
+	PurgeInterval  time.Duration `yaml:"purge_interval" toml:"purge_interval"`   // How often expired trash is purged (0 disables)
+}
 
-	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "1"))
-	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "5242880"), 10, 64)
+type PasswordConfig struct {
+	HistorySize     int `yaml:"history_size" toml:"history_size"`         // Number of previous passwords that cannot be reused
+	TemporaryLength int `yaml:"temporary_length" toml:"temporary_length"` // Length of admin-reset temporary passwords
 
-	// Parse auth service timeout
-	timeoutStr := getEnv("AUTH_SERVICE_TIMEOUT", "5s")
-	timeout, err := time.ParseDuration(timeoutStr)
-	if err != nil {
-		timeout = 5 * time.Second
-	}
+	// Password policy
+	MinLength          int    `yaml:"min_length" toml:"min_length"`
+	MaxLength          int    `yaml:"max_length" toml:"max_length"` // 0 means no limit; at most 72 bytes with bcrypt
+	RequireUpper       bool   `yaml:"require_upper" toml:"require_upper"`
+	RequireLower       bool   `yaml:"require_lower" toml:"require_lower"`
+	RequireDigit       bool   `yaml:"require_digit" toml:"require_digit"`
+	RequireSpecial     bool   `yaml:"require_special" toml:"require_special"`
+	RejectPersonalInfo bool   `yaml:"reject_personal_info" toml:"reject_personal_info"` // Reject passwords containing username, email or name
+	RejectBreached     bool   `yaml:"reject_breached" toml:"reject_breached"`           // Reject passwords in the breached password list
+	BreachedListPath   string `yaml:"breached_list" toml:"breached_list"`               // Optional extra HASH:COUNT list (Pwned Passwords format)
+
+	// Password hashing. Existing hashes of the other algorithm keep working
+	// and are upgraded the next time the password is verified.
+	HashAlgorithm     string `yaml:"hash_algorithm" toml:"hash_algorithm"` // argon2id or bcrypt
+	BcryptCost        int    `yaml:"bcrypt_cost" toml:"bcrypt_cost"`
+	Argon2Memory      uint32 `yaml:"argon2_memory" toml:"argon2_memory"` // KiB
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" toml:"argon2_iterations"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" toml:"argon2_parallelism"`
+}
+
+// BootstrapConfig describes the first admin account, created when the users
+// table is empty. Without a password a one-time setup token is issued instead.
+type BootstrapConfig struct {
+	AdminUsername     string `yaml:"admin_username" toml:"admin_username"`
+	AdminEmail        string `yaml:"admin_email" toml:"admin_email"`
+	AdminPassword     string `yaml:"admin_password" toml:"admin_password"`
+	AdminPasswordFile string `yaml:"admin_password_file" toml:"admin_password_file"` // Secret file holding the password, e.g. a Docker secret
+}
+
+// MetricsConfig controls the Prometheus endpoint. It is served on its own
+// listener so that scrapes stay off the public API port.
+type MetricsConfig struct {
+	Enabled           bool          `yaml:"enabled" toml:"enabled"`
+	ListenAddress     string        `yaml:"listen_address" toml:"listen_address"` // host:port of the metrics listener; keep it off the public network
+	Path              string        `yaml:"path" toml:"path"`
+	UserStatsInterval time.Duration `yaml:"user_stats_interval" toml:"user_stats_interval"` // How often the user gauges are recounted
+}
+
+// TracingConfig controls OpenTelemetry tracing
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`           // otlp, stdout or none
+	Endpoint    string  `yaml:"otlp_endpoint" toml:"otlp_endpoint"` // OTLP/HTTP endpoint URL; empty uses the OTEL_EXPORTER_OTLP_* variables
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`   // Share of new traces sampled; incoming sampled traces are always kept
+}
+
+// LoggingConfig controls the structured log output
+type LoggingConfig struct {
+	Level  string `yaml:"level" toml:"level"`   // debug, info, warn or error; defaults to debug in debug mode
+	Format string `yaml:"format" toml:"format"` // json or text
+}
+
+// HealthConfig controls the /readyz dependency checks
+type HealthConfig struct {
+	CheckTimeout time.Duration `yaml:"check_timeout" toml:"check_timeout"` // Per check; a check that takes longer fails
+}
+
+// ServerConfig controls the HTTP server's timeouts and graceful shutdown
+type ServerConfig struct {
+	ReadTimeout     time.Duration `yaml:"read_timeout" toml:"read_timeout"`         // Reading a whole request, including uploads
+	WriteTimeout    time.Duration `yaml:"write_timeout" toml:"write_timeout"`       // From the end of the request headers to the end of the response
+	IdleTimeout     time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`         // Keep-alive connections between requests
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`     // Time /readyz reports shutting down before the listener closes
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"` // Deadline for in-flight requests to finish
+}
+
+// CORSConfig controls which browser origins may call the API
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins"` // "*" allows any origin, but never with credentials
+	AllowedMethods   []string      `yaml:"allowed_methods" toml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" toml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" toml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age"` // How long browsers may cache a preflight response
+}
+
+// SecurityConfig controls security response headers and proxy trust
+type SecurityConfig struct {
+	HSTSMaxAge     time.Duration `yaml:"hsts_max_age" toml:"hsts_max_age"`       // 0 disables Strict-Transport-Security
+	FilesCSP       string        `yaml:"files_csp" toml:"files_csp"`             // Content-Security-Policy of uploaded files under /files
+	TrustedProxies []string      `yaml:"trusted_proxies" toml:"trusted_proxies"` // IPs or CIDRs whose X-Forwarded-For is believed; empty trusts none
+}
+
+// RateLimitConfig controls request throttling. Rules are per route group:
+// ip (every API request, by client IP), user (authenticated requests, by
+// user), search, upload and password (by user, or IP before login).
+type RateLimitConfig struct {
+	Enabled bool                     `yaml:"enabled" toml:"enabled"`
+	Store   string                   `yaml:"store" toml:"store"` // memory (per instance) or redis (shared)
+	Rules   map[string]RateLimitRule `yaml:"rules" toml:"rules"`
+}
 
+// Default returns the configuration used for everything that is not set
+func Default() *Config {
 	return &Config{
-		Port:        getEnv("PORT", "8081"),
-		GinMode:     getEnv("GIN_MODE", "debug"),
-		ServiceName: getEnv("SERVICE_NAME", "user-service"),
-		Version:     getEnv("SERVICE_VERSION", "1.0.0"),
+		Port:        "8081",
+		GinMode:     "debug",
+		ServiceName: "user-service",
+		Version:     "1.0.0",
+		Timezone:    "Asia/Jakarta",
 
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			Name:     getEnv("DB_NAME", "pesantren_users"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "postgres",
+			Password: "postgres",
+			Name:     "pesantren_users",
+			SSLMode:  "disable",
 		},
 
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       redisDB,
+			Host: "localhost",
+			Port: "6379",
+			DB:   1,
 		},
 
 		AuthService: AuthServiceConfig{
-			URL:     getEnv("AUTH_SERVICE_URL", "http://localhost:8080"),
-			Timeout: timeout,
+			URL:     "http://localhost:8080",
+			Timeout: 5 * time.Second,
 		},
 
 		Upload: UploadConfig{
-			Path:             getEnv("UPLOAD_PATH", "./uploads"),
-			MaxSize:          maxUploadSize,
+			Path:             "./uploads",
+			MaxSize:          5242880,
 			AllowedFileTypes: []string{"jpg", "jpeg", "png", "pdf", "doc", "docx"},
 		},
+
+		Lifecycle: LifecycleConfig{
+			TrashRetention: 30 * 24 * time.Hour,
+			PurgeInterval:  time.Hour,
+		},
+
+		Password: PasswordConfig{
+			HistorySize:     5,
+			TemporaryLength: 12,
+
+			MinLength:          8,
+			MaxLength:          72, // bcrypt's limit, so switching algorithms keeps the policy valid
+			RequireUpper:       true,
+			RequireLower:       true,
+			RequireDigit:       true,
+			RequireSpecial:     true,
+			RejectPersonalInfo: true,
+			RejectBreached:     true,
+
+			HashAlgorithm:     "argon2id",
+			BcryptCost:        10,
+			Argon2Memory:      19456,
+			Argon2Iterations:  2,
+			Argon2Parallelism: 1,
+		},
+
+		Bootstrap: BootstrapConfig{
+			AdminUsername: "admin",
+			AdminEmail:    "admin@pesantren.com",
+		},
+
+		Metrics: MetricsConfig{
+			Enabled:           true,
+			ListenAddress:     ":9090",
+			Path:              "/metrics",
+			UserStatsInterval: time.Minute,
+		},
+
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+
+		Logging: LoggingConfig{
+			Format: "json",
+		},
+
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+		},
+
+		Server: ServerConfig{
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+
+		CORS: CORSConfig{
+			AllowedOrigins:   []string{"*"},
+			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", "If-Match", "If-None-Match", "X-Request-ID", "X-Timezone"},
+			ExposedHeaders:   []string{"ETag", "X-Request-ID", "Content-Language", "X-Timezone", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
+			AllowCredentials: false,
+			MaxAge:           12 * time.Hour,
+		},
+
+		Security: SecurityConfig{
+			HSTSMaxAge: 365 * 24 * time.Hour,
+			FilesCSP:   "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'; frame-ancestors 'none'",
+		},
+
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Rules: map[string]RateLimitRule{
+				"ip":       {Limit: 600, Period: time.Minute},
+				"user":     {Limit: 300, Period: time.Minute},
+				"search":   {Limit: 30, Period: time.Minute},
+				"upload":   {Limit: 10, Period: time.Minute},
+				"password": {Limit: 5, Period: time.Minute},
+			},
+		},
+	}
+}
+
+// Load reads the configuration and validates it. On failure it returns the
+// configuration as far as it could be read together with a *ValidationError
+// listing every problem, not just the first.
+func Load() (*Config, error) {
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		slog.Warn(".env file not found", "error", err)
+	}
+
+	cfg := Default()
+	var problems []string
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		problems = append(problems, loadFile(path, cfg)...)
+	}
+
+	env := &envLoader{}
+	env.apply(cfg)
+	problems = append(problems, env.problems...)
+
+	problems = append(problems, cfg.readSecretFiles()...)
+
+	if cfg.Logging.Level == "" {
+		cfg.Logging.Level = "info"
+		if cfg.GinMode == "debug" {
+			cfg.Logging.Level = "debug"
+		}
+	}
+
+	problems = append(problems, cfg.Validate()...)
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// Location returns the institution's timezone, or UTC if it is not valid
+// (Validate reports that)
+func (c *Config) Location() *time.Location {
+	loc, err := LoadTimezone(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LoadTimezone loads an IANA timezone such as Asia/Jakarta. Unlike
+// time.LoadLocation it rejects "" and "Local", which are not zone names.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "Local") {
+		return nil, fmt.Errorf("%q is not an IANA timezone name", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// ValidationError lists every problem found in the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// readSecretFiles replaces passwords with the contents of their secret files
+func (c *Config) readSecretFiles() []string {
+	var problems []string
+	read := func(name, path string, dst *string) {
+		if path == "" {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: failed to read secret file: %v", name, err))
+			return
+		}
+		*dst = strings.TrimRight(string(data), "\r\n")
+	}
+
+	read("database.password_file (DB_PASSWORD_FILE)", c.Database.PasswordFile, &c.Database.Password)
+	read("redis.password_file (REDIS_PASSWORD_FILE)", c.Redis.PasswordFile, &c.Redis.Password)
+	return problems
+}
+
+// envLoader overrides configuration values with the environment variables
+// that are set, collecting values that do not parse
+type envLoader struct {
+	problems []string
+}
+
+func (e *envLoader) apply(c *Config) {
+	e.string("PORT", &c.Port)
+	e.string("GIN_MODE", &c.GinMode)
+	e.string("SERVICE_NAME", &c.ServiceName)
+	e.string("SERVICE_VERSION", &c.Version)
+	e.string("TIMEZONE", &c.Timezone)
+
+	e.string("DB_HOST", &c.Database.Host)
+	e.string("DB_PORT", &c.Database.Port)
+	e.string("DB_USER", &c.Database.User)
+	e.string("DB_PASSWORD", &c.Database.Password)
+	e.string("DB_PASSWORD_FILE", &c.Database.PasswordFile)
+	e.string("DB_NAME", &c.Database.Name)
+	e.string("DB_SSLMODE", &c.Database.SSLMode)
+
+	e.bool("REDIS_ENABLED", &c.Redis.Enabled)
+	e.string("REDIS_HOST", &c.Redis.Host)
+	e.string("REDIS_PORT", &c.Redis.Port)
+	e.string("REDIS_PASSWORD", &c.Redis.Password)
+	e.string("REDIS_PASSWORD_FILE", &c.Redis.PasswordFile)
+	e.int("REDIS_DB", &c.Redis.DB)
+
+	e.string("AUTH_SERVICE_URL", &c.AuthService.URL)
+	e.duration("AUTH_SERVICE_TIMEOUT", &c.AuthService.Timeout)
+
+	e.string("UPLOAD_PATH", &c.Upload.Path)
+	e.int64("MAX_UPLOAD_SIZE", &c.Upload.MaxSize)
+	e.list("ALLOWED_FILE_TYPES", &c.Upload.AllowedFileTypes)
+
+	e.duration("USER_TRASH_RETENTION", &c.Lifecycle.TrashRetention)
+	e.duration("USER_PURGE_INTERVAL", &c.Lifecycle.PurgeInterval)
+
+	e.int("PASSWORD_HISTORY_SIZE", &c.Password.HistorySize)
+	e.int("TEMPORARY_PASSWORD_LENGTH", &c.Password.TemporaryLength)
+	e.int("PASSWORD_MIN_LENGTH", &c.Password.MinLength)
+	e.int("PASSWORD_MAX_LENGTH", &c.Password.MaxLength)
+	e.bool("PASSWORD_REQUIRE_UPPER", &c.Password.RequireUpper)
+	e.bool("PASSWORD_REQUIRE_LOWER", &c.Password.RequireLower)
+	e.bool("PASSWORD_REQUIRE_DIGIT", &c.Password.RequireDigit)
+	e.bool("PASSWORD_REQUIRE_SPECIAL", &c.Password.RequireSpecial)
+	e.bool("PASSWORD_REJECT_PERSONAL_INFO", &c.Password.RejectPersonalInfo)
+	e.bool("PASSWORD_REJECT_BREACHED", &c.Password.RejectBreached)
+	e.string("PASSWORD_BREACHED_LIST", &c.Password.BreachedListPath)
+	e.string("PASSWORD_HASH_ALGORITHM", &c.Password.HashAlgorithm)
+	e.int("PASSWORD_BCRYPT_COST", &c.Password.BcryptCost)
+	e.uint32("PASSWORD_ARGON2_MEMORY", &c.Password.Argon2Memory)
+	e.uint32("PASSWORD_ARGON2_ITERATIONS", &c.Password.Argon2Iterations)
+	e.uint8("PASSWORD_ARGON2_PARALLELISM", &c.Password.Argon2Parallelism)
+
+	e.string("BOOTSTRAP_ADMIN_USERNAME", &c.Bootstrap.AdminUsername)
+	e.string("BOOTSTRAP_ADMIN_EMAIL", &c.Bootstrap.AdminEmail)
+	e.string("BOOTSTRAP_ADMIN_PASSWORD", &c.Bootstrap.AdminPassword)
+	e.string("BOOTSTRAP_ADMIN_PASSWORD_FILE", &c.Bootstrap.AdminPasswordFile)
+
+	e.bool("METRICS_ENABLED", &c.Metrics.Enabled)
+	e.string("METRICS_LISTEN_ADDRESS", &c.Metrics.ListenAddress)
+	e.string("METRICS_PATH", &c.Metrics.Path)
+	e.duration("METRICS_USER_STATS_INTERVAL", &c.Metrics.UserStatsInterval)
+
+	e.string("TRACING_EXPORTER", &c.Tracing.Exporter)
+	e.string("TRACING_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	e.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	e.string("LOG_LEVEL", &c.Logging.Level)
+	e.string("LOG_FORMAT", &c.Logging.Format)
+
+	e.duration("HEALTH_CHECK_TIMEOUT", &c.Health.CheckTimeout)
+
+	e.duration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+	e.duration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	e.duration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	e.duration("SERVER_SHUTDOWN_DELAY", &c.Server.ShutdownDelay)
+	e.duration("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+
+	e.list("CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins)
+	e.list("CORS_ALLOWED_METHODS", &c.CORS.AllowedMethods)
+	e.list("CORS_ALLOWED_HEADERS", &c.CORS.AllowedHeaders)
+	e.list("CORS_EXPOSED_HEADERS", &c.CORS.ExposedHeaders)
+	e.bool("CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials)
+	e.duration("CORS_MAX_AGE", &c.CORS.MaxAge)
+
+	e.duration("HSTS_MAX_AGE", &c.Security.HSTSMaxAge)
+	e.string("FILES_CSP", &c.Security.FilesCSP)
+	e.list("TRUSTED_PROXIES", &c.Security.TrustedProxies)
+
+	e.bool("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled)
+	e.string("RATE_LIMIT_STORE", &c.RateLimit.Store)
+	if c.RateLimit.Rules == nil {
+		c.RateLimit.Rules = make(map[string]RateLimitRule)
+	}
+	for _, group := range RateLimitGroups {
+		rule := c.RateLimit.Rules[group]
+		e.rateLimit("RATE_LIMIT_"+strings.ToUpper(group), &rule)
+		c.RateLimit.Rules[group] = rule
+	}
+}
+
+// lookup returns the value of key if it is set and not empty
+func (e *envLoader) lookup(key string) (string, bool) {
+	value := os.Getenv(key)
+	return value, value != ""
+}
+
+func (e *envLoader) invalid(key, value, want string) {
+	e.problems = append(e.problems, fmt.Sprintf("%s: invalid value %q, want %s", key, value, want))
+}
+
+func (e *envLoader) string(key string, dst *string) {
+	if value, ok := e.lookup(key); ok {
+		*dst = value
+	}
+}
+
+// list reads a comma-separated list, skipping empty items
+func (e *envLoader) list(key string, dst *[]string) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (e *envLoader) bool(key string, dst *bool) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			e.invalid(key, value, "true or false")
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envLoader) int(key string, dst *int) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			e.invalid(key, value, "an integer")
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envLoader) int64(key string, dst *int64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			e.invalid(key, value, "an integer")
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envLoader) uint32(key string, dst *uint32) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			e.invalid(key, value, "an integer from 0 to 4294967295")
+			return
+		}
+		*dst = uint32(parsed)
+	}
+}
+
+func (e *envLoader) uint8(key string, dst *uint8) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			e.invalid(key, value, "an integer from 0 to 255")
+			return
+		}
+		*dst = uint8(parsed)
+	}
+}
+
+func (e *envLoader) float(key string, dst *float64) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.invalid(key, value, "a number")
+			return
+		}
+		*dst = parsed
+	}
+}
+
+func (e *envLoader) duration(key string, dst *time.Duration) {
+	if value, ok := e.lookup(key); ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			e.invalid(key, value, "a duration such as 30s or 5m")
+			return
+		}
+		*dst = parsed
 	}
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+func (e *envLoader) rateLimit(key string, dst *RateLimitRule) {
+	if value, ok := e.lookup(key); ok {
+		if err := dst.UnmarshalText([]byte(value)); err != nil {
+			e.invalid(key, value, "<requests>/<period> such as 100/1m, or off")
+		}
 	}
-	return defaultValue
 }
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile reads a YAML (.yaml, .yml) or TOML (.toml) file over cfg. Keys
+// that are missing keep their value; unknown keys are reported, since they
+// are usually typos.
+func loadFile(path string, cfg *Config) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{fmt.Sprintf("CONFIG_FILE: %v", err)}
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err := decoder.Decode(cfg)
+		var typeErr *yaml.TypeError
+		switch {
+		case errors.As(err, &typeErr):
+			problems := make([]string, len(typeErr.Errors))
+			for i, msg := range typeErr.Errors {
+				problems[i] = fmt.Sprintf("%s: %s", path, msg)
+			}
+			return problems
+		case err != nil && !errors.Is(err, io.EOF):
+			return []string{fmt.Sprintf("%s: %v", path, err)}
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %v", path, err)}
+		}
+		var problems []string
+		for _, key := range meta.Undecoded() {
+			problems = append(problems, fmt.Sprintf("%s: unknown key %q", path, key.String()))
+		}
+		return problems
+	default:
+		return []string{fmt.Sprintf("CONFIG_FILE: %s is neither .yaml, .yml nor .toml", path)}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitGroups are the route groups that have a rate limit rule
+var RateLimitGroups = []string{"ip", "user", "search", "upload", "password"}
+
+// RateLimitRule allows Limit requests per Period, in bursts of up to Limit.
+// A zero Limit disables the rule. It is written as "100/1m", or "off".
+type RateLimitRule struct {
+	Limit  int
+	Period time.Duration
+}
+
+// MarshalText writes the rule as "<requests>/<period>" or "off"
+func (r RateLimitRule) MarshalText() ([]byte, error) {
+	if r.Limit == 0 {
+		return []byte("off"), nil
+	}
+	return []byte(fmt.Sprintf("%d/%s", r.Limit, r.Period)), nil
+}
+
+// UnmarshalText parses "<requests>/<period>", "off" or "0"
+func (r *RateLimitRule) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+	if value == "off" || value == "0" {
+		*r = RateLimitRule{}
+		return nil
+	}
+
+	limitStr, periodStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return fmt.Errorf("rate limit %q is not <requests>/<period>", value)
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		return fmt.Errorf("rate limit %q has an invalid request count", value)
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return fmt.Errorf("rate limit %q has an invalid period", value)
+	}
+
+	*r = RateLimitRule{Limit: limit, Period: period}
+	return nil
+}
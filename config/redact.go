@@ -0,0 +1,34 @@
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces secrets in Redacted output
+const Redacted = "[REDACTED]"
+
+// Redacted returns the configuration keyed like the config file, with
+// passwords replaced. Secret file paths are shown, their contents are not.
+func (c *Config) Redacted() (map[string]interface{}, error) {
+	copied := *c
+	redact := func(secret *string) {
+		if *secret != "" {
+			*secret = Redacted
+		}
+	}
+	redact(&copied.Database.Password)
+	redact(&copied.Redis.Password)
+	redact(&copied.Bootstrap.AdminPassword)
+
+	// Round-trip through YAML so durations and rate limit rules read like
+	// they are written, e.g. "30s" and "100/1m0s"
+	data, err := yaml.Marshal(&copied)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
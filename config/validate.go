@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// problemList collects validation problems. Each names the config file key
+// and the environment variable, since either may have set the value.
+type problemList []string
+
+func (p *problemList) add(key, env, format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf("%s (%s): %s", key, env, fmt.Sprintf(format, args...)))
+}
+
+// oneOf reports whether value is one of allowed
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// validPort reports whether port is a TCP port number
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+// Validate returns every problem with the configuration, or nil
+func (c *Config) Validate() []string {
+	var p problemList
+
+	if !validPort(c.Port) {
+		p.add("port", "PORT", "must be a port number, got %q", c.Port)
+	}
+	if !oneOf(c.GinMode, "debug", "release", "test") {
+		p.add("gin_mode", "GIN_MODE", "must be debug, release or test, got %q", c.GinMode)
+	}
+	if _, err := LoadTimezone(c.Timezone); err != nil {
+		p.add("timezone", "TIMEZONE", "must be an IANA timezone such as Asia/Jakarta, got %q", c.Timezone)
+	}
+
+	// Database
+	if c.Database.Host == "" {
+		p.add("database.host", "DB_HOST", "is required")
+	}
+	if !validPort(c.Database.Port) {
+		p.add("database.port", "DB_PORT", "must be a port number, got %q", c.Database.Port)
+	}
+	if c.Database.User == "" {
+		p.add("database.user", "DB_USER", "is required")
+	}
+	if c.Database.Name == "" {
+		p.add("database.name", "DB_NAME", "is required")
+	}
+	if !oneOf(c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full") {
+		p.add("database.ssl_mode", "DB_SSLMODE", "must be disable, allow, prefer, require, verify-ca or verify-full, got %q", c.Database.SSLMode)
+	}
+
+	// Redis
+	if c.Redis.Enabled {
+		if c.Redis.Host == "" {
+			p.add("redis.host", "REDIS_HOST", "is required when Redis is enabled")
+		}
+		if !validPort(c.Redis.Port) {
+			p.add("redis.port", "REDIS_PORT", "must be a port number, got %q", c.Redis.Port)
+		}
+		if c.Redis.DB < 0 {
+			p.add("redis.db", "REDIS_DB", "must not be negative")
+		}
+	}
+
+	// Auth service
+	if u, err := url.Parse(c.AuthService.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.add("auth_service.url", "AUTH_SERVICE_URL", "must be an http or https URL, got %q", c.AuthService.URL)
+	}
+	if c.AuthService.Timeout <= 0 {
+		p.add("auth_service.timeout", "AUTH_SERVICE_TIMEOUT", "must be positive")
+	}
+
+	// Uploads
+	if c.Upload.Path == "" {
+		p.add("upload.path", "UPLOAD_PATH", "is required")
+	}
+	if c.Upload.MaxSize <= 0 {
+		p.add("upload.max_size", "MAX_UPLOAD_SIZE", "must be positive")
+	}
+	if len(c.Upload.AllowedFileTypes) == 0 {
+		p.add("upload.allowed_file_types", "ALLOWED_FILE_TYPES", "must list at least one file type")
+	}
+	for _, fileType := range c.Upload.AllowedFileTypes {
+		if fileType == "" || strings.ContainsAny(fileType, "./ ") {
+			p.add("upload.allowed_file_types", "ALLOWED_FILE_TYPES", "%q is not an extension such as png", fileType)
+		}
+	}
+
+	// Lifecycle
+	if c.Lifecycle.TrashRetention <= 0 {
+		p.add("lifecycle.trash_retention", "USER_TRASH_RETENTION", "must be positive")
+	}
+	if c.Lifecycle.PurgeInterval < 0 {
+		p.add("lifecycle.purge_interval", "USER_PURGE_INTERVAL", "must not be negative")
+	}
+
+	// Passwords: reject settings that no password could satisfy
+	if c.Password.HistorySize < 0 {
+		p.add("password.history_size", "PASSWORD_HISTORY_SIZE", "must not be negative")
+	}
+	if c.Password.TemporaryLength < 8 {
+		p.add("password.temporary_length", "TEMPORARY_PASSWORD_LENGTH", "must be at least 8")
+	}
+	if c.Password.MinLength < 1 {
+		p.add("password.min_length", "PASSWORD_MIN_LENGTH", "must be at least 1")
+	}
+	if c.Password.MaxLength != 0 && c.Password.MaxLength < c.Password.MinLength {
+		p.add("password.max_length", "PASSWORD_MAX_LENGTH", "must not be below PASSWORD_MIN_LENGTH")
+	}
+	// Temporary passwords should themselves meet the length policy
+	if c.Password.TemporaryLength < c.Password.MinLength {
+		p.add("password.temporary_length", "TEMPORARY_PASSWORD_LENGTH", "must not be below PASSWORD_MIN_LENGTH")
+	}
+	if c.Password.MaxLength != 0 && c.Password.TemporaryLength > c.Password.MaxLength {
+		p.add("password.temporary_length", "TEMPORARY_PASSWORD_LENGTH", "must not exceed PASSWORD_MAX_LENGTH")
+	}
+	switch c.Password.HashAlgorithm {
+	case "argon2id":
+	case "bcrypt":
+		// bcrypt cannot hash more than 72 bytes, so "no limit" is not possible either
+		if c.Password.MaxLength == 0 || c.Password.MaxLength > 72 {
+			p.add("password.max_length", "PASSWORD_MAX_LENGTH", "must be between 1 and 72 with bcrypt")
+		}
+		if c.Password.BcryptCost != 0 && (c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31) {
+			p.add("password.bcrypt_cost", "PASSWORD_BCRYPT_COST", "must be between 4 and 31")
+		}
+	default:
+		p.add("password.hash_algorithm", "PASSWORD_HASH_ALGORITHM", "must be argon2id or bcrypt, got %q", c.Password.HashAlgorithm)
+	}
+
+	// Metrics and tracing
+	if c.Metrics.Enabled {
+		if _, port, err := net.SplitHostPort(c.Metrics.ListenAddress); err != nil || !validPort(port) {
+			p.add("metrics.listen_address", "METRICS_LISTEN_ADDRESS", "must be host:port, got %q", c.Metrics.ListenAddress)
+		} else if port == c.Port {
+			p.add("metrics.listen_address", "METRICS_LISTEN_ADDRESS", "must not use the API port %s", c.Port)
+		}
+	}
+	if !strings.HasPrefix(c.Metrics.Path, "/") {
+		p.add("metrics.path", "METRICS_PATH", "must start with /, got %q", c.Metrics.Path)
+	}
+	if c.Metrics.UserStatsInterval < 0 {
+		p.add("metrics.user_stats_interval", "METRICS_USER_STATS_INTERVAL", "must not be negative")
+	}
+	if !oneOf(c.Tracing.Exporter, "none", "stdout", "otlp") {
+		p.add("tracing.exporter", "TRACING_EXPORTER", "must be otlp, stdout or none, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || u.Host == "" {
+			p.add("tracing.otlp_endpoint", "TRACING_OTLP_ENDPOINT", "must be a URL, got %q", c.Tracing.Endpoint)
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		p.add("tracing.sample_ratio", "TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+	}
+
+	// Logging
+	if !oneOf(strings.ToLower(c.Logging.Level), "debug", "info", "warn", "error") {
+		p.add("logging.level", "LOG_LEVEL", "must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+	if !oneOf(strings.ToLower(c.Logging.Format), "json", "text") {
+		p.add("logging.format", "LOG_FORMAT", "must be json or text, got %q", c.Logging.Format)
+	}
+
+	// Health checks and server
+	if c.Health.CheckTimeout <= 0 {
+		p.add("health.check_timeout", "HEALTH_CHECK_TIMEOUT", "must be positive")
+	}
+	if c.Server.ReadTimeout < 0 {
+		p.add("server.read_timeout", "SERVER_READ_TIMEOUT", "must not be negative")
+	}
+	if c.Server.WriteTimeout < 0 {
+		p.add("server.write_timeout", "SERVER_WRITE_TIMEOUT", "must not be negative")
+	}
+	if c.Server.IdleTimeout < 0 {
+		p.add("server.idle_timeout", "SERVER_IDLE_TIMEOUT", "must not be negative")
+	}
+	if c.Server.ShutdownDelay < 0 {
+		p.add("server.shutdown_delay", "SERVER_SHUTDOWN_DELAY", "must not be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		p.add("server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", "must be positive")
+	}
+
+	// CORS and security
+	if len(c.CORS.AllowedOrigins) == 0 {
+		p.add("cors.allowed_origins", "CORS_ALLOWED_ORIGINS", "must list at least one origin, or *")
+	}
+	if c.CORS.AllowCredentials && oneOf("*", c.CORS.AllowedOrigins...) {
+		p.add("cors.allow_credentials", "CORS_ALLOW_CREDENTIALS", "cannot be used with the * origin, list the origins instead")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			p.add("cors.allowed_origins", "CORS_ALLOWED_ORIGINS", "%q is not an origin such as https://app.example.com", origin)
+		}
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		p.add("security.hsts_max_age", "HSTS_MAX_AGE", "must not be negative")
+	}
+	for _, proxy := range c.Security.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				p.add("security.trusted_proxies", "TRUSTED_PROXIES", "%q is neither an IP nor a CIDR", proxy)
+			}
+		}
+	}
+
+	// Rate limiting
+	if c.RateLimit.Enabled {
+		switch c.RateLimit.Store {
+		case "memory":
+		case "redis":
+			if !c.Redis.Enabled {
+				p.add("rate_limit.store", "RATE_LIMIT_STORE", "redis needs Redis to be enabled (REDIS_ENABLED)")
+			}
+		default:
+			p.add("rate_limit.store", "RATE_LIMIT_STORE", "must be memory or redis, got %q", c.RateLimit.Store)
+		}
+		for group := range c.RateLimit.Rules {
+			if !oneOf(group, RateLimitGroups...) {
+				p.add("rate_limit.rules", "RATE_LIMIT_*", "unknown group %q, want one of %s", group, strings.Join(RateLimitGroups, ", "))
+			}
+		}
+	}
+
+	return p
+}
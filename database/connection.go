@@ -1,17 +1,18 @@
 package database
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
 	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/metrics"
 	"gitlab.com/nodiviti/user-service/models"
-	"gitlab.com/nodiviti/user-service/utils"
 )
 
 var (
@@ -19,8 +20,9 @@ var (
 )
 
 func InitDatabase(cfg *config.Config) error {
-	// PostgreSQL connection string
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=Asia/Jakarta",
+	// PostgreSQL connection string. Sessions run in UTC; timestamps are
+	// converted to the institution's or caller's timezone when rendered.
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
 		cfg.Database.Host,
 		cfg.Database.User,
 		cfg.Database.Password,
@@ -29,19 +31,12 @@ func InitDatabase(cfg *config.Config) error {
 		cfg.Database.SSLMode,
 	)
 
-	// GORM config
-	var gormLogger logger.Interface
-	if cfg.GinMode == "debug" {
-		gormLogger = logger.Default.LogMode(logger.Info) // Show SQL queries in debug mode
-	} else {
-		gormLogger = logger.Default.LogMode(logger.Error)
-	}
-
+	// GORM config: SQL is logged at debug level, slow queries as warnings
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger,
+		Logger: logging.NewGormLogger(200 * time.Millisecond),
 		NowFunc: func() time.Time {
-			return time.Now().In(time.FixedZone("WIB", 7*3600)) // UTC+7 for Indonesia
+			return time.Now().UTC()
 		},
 	})
 
@@ -49,6 +44,13 @@ func InitDatabase(cfg *config.Config) error {
 		return fmt.Errorf("failed to connect to database: %v", err)
 	}
 
+	if err := registerMetricsCallbacks(DB); err != nil {
+		return fmt.Errorf("failed to register query metrics: %v", err)
+	}
+	if err := registerTracingCallbacks(DB); err != nil {
+		return fmt.Errorf("failed to register query tracing: %v", err)
+	}
+
 	// Get underlying SQL DB for connection pool configuration
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -59,22 +61,39 @@ func InitDatabase(cfg *config.Config) error {
 	sqlDB.SetMaxOpenConns(25)
 	sqlDB.SetMaxIdleConns(5)
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	metrics.RegisterDBStats(sqlDB, cfg.Database.Name)
 
 	// Test connection
 	if err = sqlDB.Ping(); err != nil {
 		return fmt.Errorf("failed to ping database: %v", err)
 	}
 
-	log.Println("✅ Connected to PostgreSQL with GORM (User Service)")
+	slog.Info("Connected to PostgreSQL", "host", cfg.Database.Host, "database", cfg.Database.Name)
 	return nil
 }
 
 // AutoMigrate runs database migrations for single users table
 func AutoMigrate() error {
-	log.Println("🔄 Running GORM auto-migrations for users table...")
+	slog.Info("Running database migrations")
+
+	// Unique indexes used to cover trashed rows too, which blocked reusing the
+	// username, email or IDs of a deleted user. They are now partial indexes
+	// over live rows only (see the *_live indexes on models.User).
+	legacyUniqueIndexes := []string{
+		"DROP INDEX IF EXISTS idx_users_username",
+		"DROP INDEX IF EXISTS idx_users_email",
+		"DROP INDEX IF EXISTS idx_users_employee_id",
+		"DROP INDEX IF EXISTS idx_users_student_id",
+		"DROP INDEX IF EXISTS idx_users_nisn",
+	}
+	for _, dropSQL := range legacyUniqueIndexes {
+		if result := DB.Exec(dropSQL); result.Error != nil {
+			return fmt.Errorf("failed to drop legacy unique index: %v", result.Error)
+		}
+	}
 
 	// Auto migrate the single users table
-	err := DB.AutoMigrate(&models.User{})
+	err := DB.AutoMigrate(&models.User{}, &models.UserRevision{}, &models.ProfileChangeRequest{}, &models.PasswordHistory{})
 	if err != nil {
 		return fmt.Errorf("failed to auto-migrate users table: %v", err)
 	}
@@ -85,7 +104,13 @@ func AutoMigrate() error {
 		return fmt.Errorf("failed to create additional indexes: %v", err)
 	}
 
-	log.Println("✅ GORM auto-migration completed successfully (single users table)")
+	// Set up full-text and trigram search
+	err = createSearchIndexes()
+	if err != nil {
+		return fmt.Errorf("failed to set up search indexes: %v", err)
+	}
+
+	slog.Info("Database migrations completed")
 	return nil
 }
 
@@ -97,72 +122,78 @@ func createAdditionalIndexes() error {
 		"CREATE INDEX IF NOT EXISTS idx_users_role_status ON users(role, status) WHERE deleted_at IS NULL",
 		"CREATE INDEX IF NOT EXISTS idx_users_class_year ON users(class_level, academic_year) WHERE role = 'student' AND deleted_at IS NULL",
 		"CREATE INDEX IF NOT EXISTS idx_users_specialization ON users(specialization) WHERE role = 'teacher' AND deleted_at IS NULL",
-		"CREATE INDEX IF NOT EXISTS idx_users_employee_id ON users(employee_id) WHERE employee_id IS NOT NULL AND deleted_at IS NULL",
-		"CREATE INDEX IF NOT EXISTS idx_users_student_id ON users(student_id) WHERE student_id IS NOT NULL AND deleted_at IS NULL",
 		"CREATE INDEX IF NOT EXISTS idx_users_full_name ON users(full_name) WHERE full_name IS NOT NULL AND deleted_at IS NULL",
 		"CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trash ON users(deleted_at) WHERE deleted_at IS NOT NULL",
 	}
 
 	for _, indexSQL := range indexes {
 		result := DB.Exec(indexSQL)
 		if result.Error != nil {
-			log.Printf("Warning: Failed to create index: %v", result.Error)
+			slog.Warn("Failed to create index", "error", result.Error)
 			// Continue with other indexes
 		}
 	}
 
-	log.Println("✅ Additional indexes created for single users table")
+	slog.Info("Additional indexes created")
 	return nil
 }
 
-// SeedData creates initial admin user
-func SeedData() error {
-	log.Println("🌱 Checking for seed data...")
-
-	// Check if we have any users
-	var userCount int64
-	DB.Model(&models.User{}).Count(&userCount)
-
-	if userCount == 0 {
-		log.Println("🌱 Creating initial admin user...")
+// createSearchIndexes enables pg_trgm and creates the search column and indexes
+func createSearchIndexes() error {
+	// Required objects: search queries depend on them
+	setup := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		// Normalizes common Indonesian/Arabic transliteration variants so that
+		// e.g. "Mohamad", "Muhamad" and "Muhammad" compare equal
+		`CREATE OR REPLACE FUNCTION user_search_norm(input text) RETURNS text
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+			SELECT regexp_replace(
+				regexp_replace(
+					replace(replace(replace(replace(replace(replace(replace(
+						lower(coalesce(input, '')),
+						'oe', 'u'), 'dj', 'j'), 'tj', 'c'), 'sj', 'sy'), 'ch', 'kh'), 'ph', 'f'), 'q', 'k'),
+					'\mmoh', 'muh', 'g'),
+				'([a-z])\1+', '\1', 'g')
+		$$`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			to_tsvector('simple',
+				coalesce(full_name, '') || ' ' ||
+				coalesce(username, '') || ' ' ||
+				coalesce(email, '') || ' ' ||
+				coalesce(parent_name, ''))
+		) STORED`,
+	}
 
-		// Hash default password
-		hashedPassword, err := utils.HashPassword("Admin123!@#")
-		if err != nil {
-			return fmt.Errorf("failed to hash admin password: %v", err)
+	for _, setupSQL := range setup {
+		if result := DB.Exec(setupSQL); result.Error != nil {
+			return result.Error
 		}
+	}
 
-		// Create default admin user
-		adminUser := models.User{
-			Username:     "admin",
-			Email:        "admin@pesantren.com",
-			PasswordHash: hashedPassword,
-			Role:         "admin",
-			IsActive:     true,
-			FullName:     stringPtr("System Administrator"),
-		}
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING gin(search_vector) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trgm_full_name ON users USING gin(user_search_norm(full_name) gin_trgm_ops) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trgm_username ON users USING gin(user_search_norm(username) gin_trgm_ops) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trgm_email ON users USING gin(user_search_norm(email) gin_trgm_ops) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trgm_parent_name ON users USING gin(user_search_norm(parent_name) gin_trgm_ops) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trgm_student_id ON users USING gin(student_id gin_trgm_ops) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_trgm_employee_id ON users USING gin(employee_id gin_trgm_ops) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_users_nisn_prefix ON users(nisn text_pattern_ops) WHERE deleted_at IS NULL",
+	}
 
-		result := DB.Create(&adminUser)
+	for _, indexSQL := range indexes {
+		result := DB.Exec(indexSQL)
 		if result.Error != nil {
-			return fmt.Errorf("failed to create admin user: %v", result.Error)
+			slog.Warn("Failed to create search index", "error", result.Error)
+			// Continue with other indexes
 		}
-
-		log.Printf("✅ Admin user created with ID: %d", adminUser.ID)
-		log.Println("   📧 Email: admin@pesantren.com")
-		log.Println("   👤 Username: admin")
-		log.Println("   🔑 Password: Admin123!@# (please change this!)")
-	} else {
-		log.Printf("⏭️  Found %d existing users, skipping seed data", userCount)
 	}
 
+	slog.Info("Search indexes created")
 	return nil
 }
 
-// Helper function to create string pointer
-func stringPtr(s string) *string {
-	return &s
-}
-
 // GetDB returns the GORM database instance
 func GetDB() *gorm.DB {
 	return DB
@@ -181,13 +212,13 @@ func Close() error {
 			return err
 		}
 
-		log.Println("📦 Database connection closed (User Service)")
+		slog.Info("Database connection closed")
 	}
 	return nil
 }
 
-// HealthCheck checks database connectivity
-func HealthCheck() error {
+// HealthCheck checks database connectivity, giving up when ctx is done
+func HealthCheck(ctx context.Context) error {
 	if DB == nil {
 		return fmt.Errorf("database connection is nil")
 	}
@@ -197,5 +228,5 @@ func HealthCheck() error {
 		return fmt.Errorf("failed to get underlying sql.DB: %v", err)
 	}
 
-	return sqlDB.Ping()
+	return sqlDB.PingContext(ctx)
 }
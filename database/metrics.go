@@ -0,0 +1,62 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/metrics"
+)
+
+const metricsStartKey = "metrics:start"
+
+// registerMetricsCallbacks times every GORM statement and counts its errors
+func registerMetricsCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	afterCallback := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) { observeStatement(db, operation) }
+	}
+
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", startTimer),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", afterCallback("create")),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", startTimer),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", afterCallback("query")),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", startTimer),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", afterCallback("update")),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", startTimer),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", afterCallback("delete")),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", startTimer),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", afterCallback("row")),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", startTimer),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", afterCallback("raw")),
+	)
+}
+
+// startTimer records when a statement started
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+// observeStatement records a statement's duration and whether it failed
+func observeStatement(db *gorm.DB, operation string) {
+	value, ok := db.InstanceGet(metricsStartKey)
+	if !ok {
+		return
+	}
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+
+	table := db.Statement.Table
+	if table == "" {
+		table = "unknown"
+	}
+
+	metrics.DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		metrics.DBQueryErrors.WithLabelValues(operation, table).Inc()
+	}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+var (
+	Redis *redis.Client
+)
+
+// InitRedis connects to Redis when it is enabled in the configuration
+func InitRedis(cfg *config.Config) error {
+	if !cfg.Redis.Enabled {
+		return nil
+	}
+
+	Redis = redis.NewClient(&redis.Options{
+		Addr:     net.JoinHostPort(cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	if err := Redis.Ping(context.Background()).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %v", err)
+	}
+
+	slog.Info("Connected to Redis", "host", cfg.Redis.Host, "db", cfg.Redis.DB)
+	return nil
+}
+
+// GetRedis returns the Redis client, or nil when Redis is disabled
+func GetRedis() *redis.Client {
+	return Redis
+}
+
+// CloseRedis closes the Redis connection
+func CloseRedis() error {
+	if Redis != nil {
+		if err := Redis.Close(); err != nil {
+			return err
+		}
+		slog.Info("Redis connection closed")
+	}
+	return nil
+}
+
+// RedisHealthCheck checks Redis connectivity
+func RedisHealthCheck(ctx context.Context) error {
+	if Redis == nil {
+		return fmt.Errorf("redis connection is nil")
+	}
+	return Redis.Ping(ctx).Err()
+}
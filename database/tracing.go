@@ -0,0 +1,81 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/tracing"
+)
+
+const tracingSpanKey = "tracing:span"
+
+// registerTracingCallbacks wraps every GORM statement in a span, a child of
+// the span in the statement's context (see UserService.WithContext)
+func registerTracingCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	beforeCallback := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) { startSpan(db, operation) }
+	}
+
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", beforeCallback("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", beforeCallback("query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", beforeCallback("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", beforeCallback("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", beforeCallback("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", beforeCallback("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+// startSpan starts the span of a statement
+func startSpan(db *gorm.DB, operation string) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		return
+	}
+
+	_, span := tracing.Tracer().Start(ctx, "gorm."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(operation)),
+	)
+	db.InstanceSet(tracingSpanKey, span)
+}
+
+// endSpan records the statement's SQL and outcome and ends its span. The SQL
+// has placeholders only, so no values such as emails or phones are exported.
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if !span.IsRecording() {
+		return
+	}
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBResponseReturnedRows(int(db.Statement.RowsAffected)),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
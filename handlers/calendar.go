@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// wantsHijri reports whether the caller asked for Hijri date renderings
+func wantsHijri(c *gin.Context) bool {
+	return c.Query("calendar") == "hijri"
+}
+
+// location returns the timezone chosen by middleware.Timezone, or UTC
+func location(c *gin.Context) *time.Location {
+	if tz, ok := c.Get("tz"); ok {
+		if loc, ok := tz.(*time.Location); ok {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// toUserResponse converts a user to its API response in the request's
+// timezone, adding Hijri dates if requested
+func toUserResponse(c *gin.Context, user *models.User) *models.UserResponse {
+	resp := user.ToResponse().In(location(c))
+	if wantsHijri(c) {
+		resp.WithHijri()
+	}
+	return resp
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+type ChangeRequestHandler struct {
+	cfg                  *config.Config
+	validator            *validator.Validate
+	changeRequestService *services.ChangeRequestService
+}
+
+func NewChangeRequestHandler(cfg *config.Config, changeRequestService *services.ChangeRequestService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{
+		cfg:                  cfg,
+		validator:            newValidator(),
+		changeRequestService: changeRequestService,
+	}
+}
+
+// changeRequests returns the change request service bound to the request's context
+func (h *ChangeRequestHandler) changeRequests(c *gin.Context) *services.ChangeRequestService {
+	return h.changeRequestService.WithContext(c.Request.Context())
+}
+
+// SubmitMyChangeRequest proposes changes to the current user's profile
+func (h *ChangeRequestHandler) SubmitMyChangeRequest(c *gin.Context) {
+	userID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.SubmitChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	patch, err := models.ParseUserPatch(req.Changes)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format", gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := h.validator.Struct(patch.Request); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	request, err := h.changeRequests(c).Submit(userID, role, patch, req.Note)
+	if err != nil {
+		respondError(c, err, "Failed to submit change request")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    "change_request_submitted",
+		"message": localize(c, "change_request_submitted", nil, ""),
+		"data":    h.toResponse(c, request, false),
+	})
+}
+
+// GetMyChangeRequests lists the current user's change requests and their status
+func (h *ChangeRequestHandler) GetMyChangeRequests(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.changeRequests(c).ListForUser(userID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve change requests")
+		return
+	}
+
+	responses := []models.ChangeRequestResponse{}
+	for i := range requests {
+		responses = append(responses, h.toResponse(c, &requests[i], false))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "change_requests_retrieved",
+		"message": localize(c, "change_requests_retrieved", nil, ""),
+		"data":    responses,
+		"count":   len(responses),
+	})
+}
+
+// CancelMyChangeRequest withdraws one of the current user's pending change requests
+func (h *ChangeRequestHandler) CancelMyChangeRequest(c *gin.Context) {
+	userID, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid change request ID")
+		return
+	}
+
+	if err := h.changeRequests(c).Cancel(uint(requestID), userID); err != nil {
+		respondError(c, err, "Failed to cancel change request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "change_request_cancelled",
+		"message": localize(c, "change_request_cancelled", nil, ""),
+	})
+}
+
+// GetChangeRequestQueue lists change requests the reviewer may act on (admin/homeroom teacher)
+func (h *ChangeRequestHandler) GetChangeRequestQueue(c *gin.Context) {
+	reviewerID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	status := c.DefaultQuery("status", models.ChangeRequestPending)
+	if status == "all" {
+		status = ""
+	}
+
+	requests, err := h.changeRequests(c).ListQueue(reviewerID, role, status)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve change requests")
+		return
+	}
+
+	responses := []models.ChangeRequestResponse{}
+	for i := range requests {
+		responses = append(responses, h.toResponse(c, &requests[i], true))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "change_requests_retrieved",
+		"message": localize(c, "change_requests_retrieved", nil, ""),
+		"data":    responses,
+		"count":   len(responses),
+	})
+}
+
+// ApproveChangeRequest applies a pending change request (admin/homeroom teacher)
+func (h *ChangeRequestHandler) ApproveChangeRequest(c *gin.Context) {
+	reviewerID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid change request ID")
+		return
+	}
+
+	request, user, err := h.changeRequests(c).Approve(uint(requestID), reviewerID, role)
+	if err != nil {
+		respondError(c, err, "Failed to approve change request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "change_request_approved",
+		"message": localize(c, "change_request_approved", nil, ""),
+		"data":    h.toResponse(c, request, false),
+		"user":    toUserResponse(c, user),
+	})
+}
+
+// RejectChangeRequest rejects a pending change request with a reason (admin/homeroom teacher)
+func (h *ChangeRequestHandler) RejectChangeRequest(c *gin.Context) {
+	reviewerID, role, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid change request ID")
+		return
+	}
+
+	var req models.RejectChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	request, err := h.changeRequests(c).Reject(uint(requestID), reviewerID, role, req.Reason)
+	if err != nil {
+		respondError(c, err, "Failed to reject change request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "change_request_rejected",
+		"message": localize(c, "change_request_rejected", nil, ""),
+		"data":    h.toResponse(c, request, false),
+	})
+}
+
+// toResponse adds the diff (and optionally the requester) to a change request
+func (h *ChangeRequestHandler) toResponse(c *gin.Context, request *models.ProfileChangeRequest, withRequester bool) models.ChangeRequestResponse {
+	diff, _ := h.changeRequestService.Diff(request)
+	if diff == nil {
+		diff = []models.FieldChange{}
+	}
+
+	resp := models.ChangeRequestResponse{
+		ProfileChangeRequest: request.In(location(c)),
+		Diff:                 diff,
+	}
+	if withRequester {
+		resp.Requester = toUserResponse(c, &request.User)
+	}
+	return resp
+}
+
+// currentUser returns the authenticated user's ID and role from the context
+func currentUser(c *gin.Context) (uint, string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
+		return 0, "", false
+	}
+
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return uint(userID.(int)), roleStr, true
+}
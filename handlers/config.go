@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+type ConfigHandler struct {
+	cfg *config.Config
+}
+
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg}
+}
+
+// GetConfig shows the effective configuration with secrets redacted (admin only)
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	redacted, err := h.cfg.Redacted()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve configuration")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "config_retrieved",
+		"message": localize(c, "config_retrieved", nil, ""),
+		"data":    redacted,
+	})
+}
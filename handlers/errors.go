@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/locales/ar"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	ar_translations "github.com/go-playground/validator/v10/translations/ar"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/services"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// Every error response uses the same envelope:
+//
+//	{"code": "user_not_found", "error": "User not found", "fields": [...]}
+//
+// code is stable and meant for programs, error is a human readable message
+// in the request's language and fields lists per-field problems of validation
+// errors. Some responses add context such as current_version or violations.
+
+// writeError writes an error envelope with message localized by code.
+// extra adds response-specific keys.
+func writeError(c *gin.Context, status int, code, message string, extra ...gin.H) {
+	writeErrorBody(c, status, code, localize(c, code, nil, message), extra...)
+}
+
+// writeErrorBody writes an error envelope with an already localized message
+func writeErrorBody(c *gin.Context, status int, code, message string, extra ...gin.H) {
+	body := gin.H{
+		"code":  code,
+		"error": message,
+	}
+	for _, e := range extra {
+		for k, v := range e {
+			body[k] = v
+		}
+	}
+	c.JSON(status, body)
+}
+
+// respondError maps a service error to its envelope. Errors that are not
+// domain errors are logged and reported as a 500 with message.
+func respondError(c *gin.Context, err error, message string, extra ...gin.H) {
+	lang := language(c)
+
+	var policyErr *utils.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		fields := passwordViolationFields(lang, policyErr)
+		violations := make([]string, len(fields))
+		for i, field := range fields {
+			violations[i] = field.Message
+		}
+		writeError(c, http.StatusBadRequest, "password_policy", "Password does not meet requirements", append(extra, gin.H{
+			"fields":     fields,
+			"violations": violations,
+		})...)
+		return
+	}
+
+	domainErr, ok := services.AsError(err)
+	if !ok {
+		ctx := c.Request.Context()
+		logging.FromContext(ctx).ErrorContext(ctx, message, "error", err)
+		writeError(c, http.StatusInternalServerError, "internal_error", message, extra...)
+		return
+	}
+
+	params := map[string]string{"field": domainErr.Field}
+	for k, v := range domainErr.Params {
+		params[k] = v
+	}
+
+	details := gin.H{}
+	if domainErr.Field != "" {
+		details["field"] = domainErr.Field
+	}
+	if len(domainErr.Fields) > 0 {
+		details["fields"] = localizeFields(c, domainErr.Fields, domainErr.Params)
+	}
+	text := localize(c, domainErr.Code, params, capitalize(domainErr.Message))
+	writeErrorBody(c, errorStatus(domainErr), domainErr.Code, text, append(extra, details)...)
+}
+
+// errorStatus returns the HTTP status of a domain error kind
+func errorStatus(err *services.Error) int {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondValidationError reports struct validation failures field by field
+func respondValidationError(c *gin.Context, err error) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		writeError(c, http.StatusBadRequest, "validation_failed", "Validation failed", gin.H{
+			"details": err.Error(),
+		})
+		return
+	}
+
+	writeError(c, http.StatusBadRequest, "validation_failed", "Validation failed", gin.H{
+		"fields": validationFields(c, validationErrors),
+	})
+}
+
+// validationFields translates struct validation failures into the request's language
+func validationFields(c *gin.Context, validationErrors validator.ValidationErrors) []services.FieldError {
+	return translateValidationErrors(language(c), validationErrors)
+}
+
+// translateValidationErrors translates struct validation failures into lang
+func translateValidationErrors(lang utils.Language, validationErrors validator.ValidationErrors) []services.FieldError {
+	trans, _ := translators.GetTranslator(string(lang))
+	fields := make([]services.FieldError, len(validationErrors))
+	for i, fieldErr := range validationErrors {
+		fields[i] = services.FieldError{
+			Field:   fieldErr.Field(),
+			Code:    fieldErr.Tag(),
+			Message: fieldErr.Translate(trans),
+		}
+	}
+	return fields
+}
+
+// passwordViolationFields lists password policy violations in lang
+func passwordViolationFields(lang utils.Language, policyErr *utils.PasswordPolicyError) []services.FieldError {
+	fields := make([]services.FieldError, len(policyErr.Violations))
+	for i, violation := range policyErr.Violations {
+		fields[i] = services.FieldError{Field: "password", Code: violation.Code, Message: violation.Message(lang)}
+	}
+	return fields
+}
+
+// localizeFields returns fields with their messages in the request's language.
+// params fills placeholders other than {field}.
+func localizeFields(c *gin.Context, fields []services.FieldError, params map[string]string) []services.FieldError {
+	localized := make([]services.FieldError, len(fields))
+	for i, field := range fields {
+		fieldParams := map[string]string{"field": field.Field}
+		for k, v := range params {
+			fieldParams[k] = v
+		}
+		field.Message = localize(c, "field_"+field.Code, fieldParams, field.Message)
+		localized[i] = field
+	}
+	return localized
+}
+
+// language returns the response language chosen by middleware.Language,
+// falling back to the request's Accept-Language header
+func language(c *gin.Context) utils.Language {
+	if lang, ok := c.Get("lang"); ok {
+		if l, ok := lang.(utils.Language); ok {
+			return l
+		}
+	}
+	return utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
+// localize returns the catalog text of code in the request's language
+func localize(c *gin.Context, code string, params map[string]string, fallback string) string {
+	return utils.Localize(language(c), code, params, fallback)
+}
+
+// translators holds the validator's field error translations, one per catalog language
+var translators = ut.New(en.New(), en.New(), id.New(), ar.New())
+
+// validatorTranslations registers each language's field error translations
+var validatorTranslations = map[utils.Language]func(*validator.Validate, ut.Translator) error{
+	utils.English:    en_translations.RegisterDefaultTranslations,
+	utils.Indonesian: id_translations.RegisterDefaultTranslations,
+	utils.Arabic:     ar_translations.RegisterDefaultTranslations,
+}
+
+// sharedValidator builds the validator once. It reports fields by their JSON
+// names and translates field errors into every catalog language.
+var sharedValidator = sync.OnceValues(func() (*validator.Validate, error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	for _, lang := range utils.SupportedLanguages {
+		trans, _ := translators.GetTranslator(string(lang))
+		if err := validatorTranslations[lang](validate, trans); err != nil {
+			return validate, fmt.Errorf("failed to register %s validation messages: %w", lang, err)
+		}
+		if text, ok := timezoneTranslations[lang]; ok {
+			if err := registerTranslation(validate, trans, "timezone", text); err != nil {
+				return validate, err
+			}
+		}
+	}
+	return validate, nil
+})
+
+// InitValidator builds the shared validator, returning any error in
+// registering its messages. Call it before creating handlers.
+func InitValidator() error {
+	_, err := sharedValidator()
+	return err
+}
+
+// newValidator returns the shared validator; InitValidator reports whether
+// all its messages were registered
+func newValidator() *validator.Validate {
+	validate, _ := sharedValidator()
+	return validate
+}
+
+// ValidateStruct checks v with the shared validator outside of a request, e.g.
+// from the CLI. Field failures come back as a Validation error with English
+// messages.
+func ValidateStruct(v interface{}) error {
+	validate, err := sharedValidator()
+	if err != nil {
+		return err
+	}
+
+	err = validate.Struct(v)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	return services.ValidationError("validation failed", translateValidationErrors(utils.English, validationErrors)...)
+}
+
+// timezoneTranslations covers the timezone tag where the language pack does not
+var timezoneTranslations = map[utils.Language]string{
+	utils.English: "{0} must be an IANA timezone such as Asia/Jakarta",
+	utils.Arabic:  "يجب أن يكون {0} منطقة زمنية من IANA مثل Asia/Jakarta",
+}
+
+// registerTranslation adds the message of a validation tag in one language
+func registerTranslation(validate *validator.Validate, trans ut.Translator, tag, text string) error {
+	err := validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+		return ut.Add(tag, text, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		message, _ := ut.T(tag, fe.Field())
+		return message
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register %s validation message: %w", tag, err)
+	}
+	return nil
+}
+
+// capitalize upper-cases the first letter of a service message
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+// userETag returns the weak ETag of a user version as rendered for this
+// request, e.g. W/"42-7-1f0c9a3e". The last part hashes what else shapes the
+// body (calendar, fields, timezone and language), so a cached variant is
+// never revalidated for another. If-Match only compares the id and version.
+func userETag(c *gin.Context, user *models.User) string {
+	variant := fnv.New32a()
+	for _, part := range []string{c.Query("calendar"), c.Query("fields"), location(c).String(), string(language(c))} {
+		variant.Write([]byte(part))
+		variant.Write([]byte{0})
+	}
+	return fmt.Sprintf(`W/"%d-%d-%08x"`, user.ID, user.Version, variant.Sum32())
+}
+
+// setETag sets the ETag header and reports whether If-None-Match matched,
+// in which case a 304 has been written and the handler should return.
+// If-None-Match uses weak comparison.
+func setETag(c *gin.Context, user *models.User) bool {
+	etag := userETag(c, user)
+	c.Header("ETag", etag)
+
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == opaque || candidate == "*" {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// requireIfMatch reads the expected version from If-Match. "*" only says the
+// user exists and would skip the version check, so it is refused like a
+// missing header. On failure a response has been written.
+//
+// This deliberately departs from RFC 7232, which requires strong comparison
+// for If-Match: our ETags are weak because the envelope around a user differs
+// between endpoints, but the id and version they carry identify the stored
+// user exactly, and that is all a precondition on an update needs.
+func requireIfMatch(c *gin.Context, userID uint) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		writeError(c, http.StatusPreconditionRequired, "if_match_required", "If-Match header required")
+		return 0, false
+	}
+	if header == "*" {
+		writeError(c, http.StatusPreconditionRequired, "if_match_wildcard", "If-Match must carry the user's ETag, not *")
+		return 0, false
+	}
+
+	// "id-version", optionally followed by the representation variant
+	etag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	parts := strings.SplitN(etag, "-", 3)
+	if len(parts) < 2 {
+		writeError(c, http.StatusBadRequest, "invalid_if_match", "Invalid If-Match header")
+		return 0, false
+	}
+	id, idErr := strconv.ParseUint(parts[0], 10, 32)
+	version, versionErr := strconv.ParseUint(parts[1], 10, 32)
+	if idErr != nil || versionErr != nil || version == 0 {
+		writeError(c, http.StatusBadRequest, "invalid_if_match", "Invalid If-Match header")
+		return 0, false
+	}
+
+	if uint(id) != userID {
+		writeError(c, http.StatusPreconditionFailed, "if_match_mismatch", "If-Match does not refer to this user")
+		return 0, false
+	}
+
+	return uint(version), true
+}
+
+// respondUpdateError writes the response for a failed versioned update
+func respondUpdateError(c *gin.Context, userID uint, err error, message string) {
+	var conflict *services.VersionConflictError
+	if errors.As(err, &conflict) {
+		c.Header("ETag", userETag(c, &models.User{ID: userID, Version: conflict.CurrentVersion}))
+		writeError(c, http.StatusPreconditionFailed, "version_conflict", "User was modified by someone else", gin.H{
+			"current_version": conflict.CurrentVersion,
+			"changed_fields":  conflict.ChangedFields,
+		})
+		return
+	}
+
+	respondError(c, err, message)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/database"
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// healthCheck is one dependency checked by /readyz
+type healthCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// checkResult is the outcome of one healthCheck. The probes are public, so
+// why a check failed is only logged: it names hosts, URLs and paths.
+type checkResult struct {
+	Status    string  `json:"status"` // up or down
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type HealthHandler struct {
+	cfg          *config.Config
+	checks       []healthCheck
+	startedAt    time.Time
+	shuttingDown atomic.Bool
+}
+
+// NewHealthHandler checks the database, the auth service, the upload
+// directory and, when enabled, Redis
+func NewHealthHandler(cfg *config.Config) *HealthHandler {
+	authClient := utils.NewAuthClient(cfg)
+
+	checks := []healthCheck{
+		{name: "database", run: database.HealthCheck},
+		{name: "auth_service", run: authClient.Ping},
+		{name: "upload_storage", run: func(ctx context.Context) error {
+			return checkWritable(cfg.Upload.Path)
+		}},
+	}
+	if cfg.Redis.Enabled {
+		checks = append(checks, healthCheck{name: "redis", run: database.RedisHealthCheck})
+	}
+
+	return &HealthHandler{
+		cfg:       cfg,
+		checks:    checks,
+		startedAt: time.Now(),
+	}
+}
+
+// SetShuttingDown makes /readyz report not ready so load balancers stop
+// sending traffic while in-flight requests drain
+func (h *HealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Livez reports that the process is up. It checks no dependencies, so an
+// outage of the database does not get the service restarted.
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "alive",
+		"service":        h.cfg.ServiceName,
+		"version":        h.cfg.Version,
+		"uptime_seconds": int64(time.Since(h.startedAt).Seconds()),
+	})
+}
+
+// Readyz runs every dependency check concurrently, each within the
+// configured timeout, and answers 503 if any fails or during shutdown
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	results := h.runChecks(c.Request.Context())
+
+	status := "ready"
+	for _, result := range results {
+		if result.Status != "up" {
+			status = "not_ready"
+		}
+	}
+	if h.shuttingDown.Load() {
+		status = "shutting_down"
+	}
+
+	code := http.StatusOK
+	if status != "ready" {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status":  status,
+		"service": h.cfg.ServiceName,
+		"version": h.cfg.Version,
+		"checks":  results,
+	})
+}
+
+// runChecks runs the checks concurrently and collects their results by name
+func (h *HealthHandler) runChecks(ctx context.Context) map[string]checkResult {
+	results := make(map[string]checkResult, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check healthCheck) {
+			defer wg.Done()
+			result := h.runCheck(ctx, check)
+			mu.Lock()
+			results[check.name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// runCheck runs one check, failing it once the timeout passes even if the
+// check ignores its context
+func (h *HealthHandler) runCheck(ctx context.Context, check healthCheck) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.Health.CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.run(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", h.cfg.Health.CheckTimeout)
+	}
+
+	result := checkResult{
+		Status:    "up",
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = "down"
+		logging.FromContext(ctx).WarnContext(ctx, "Health check failed",
+			"check", check.name, "latency_ms", result.LatencyMS, "error", err)
+	}
+	return result
+}
+
+// checkWritable creates and removes a file in dir
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return fmt.Errorf("upload directory not writable: %v", err)
+	}
+	name := file.Name()
+	file.Close()
+	return os.Remove(name)
+}
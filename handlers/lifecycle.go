@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// ActivateUser reactivates a deactivated user account (admin only)
+func (h *UserHandler) ActivateUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
+		return
+	}
+
+	err = h.users(c).ActivateUser(uint(userID))
+	if err != nil {
+		respondError(c, err, "Failed to activate user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "user_activated",
+		"message": localize(c, "user_activated", nil, ""),
+	})
+}
+
+// DeleteUser moves a user to the trash (admin only)
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
+		return
+	}
+
+	err = h.users(c).DeleteUser(uint(userID))
+	if err != nil {
+		respondError(c, err, "Failed to delete user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":           "user_trashed",
+		"message":        localize(c, "user_trashed", nil, ""),
+		"retention_days": int(h.cfg.Lifecycle.TrashRetention.Hours() / 24),
+	})
+}
+
+// GetTrash lists users in the trash (admin only)
+func (h *UserHandler) GetTrash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	users, total, err := h.users(c).GetTrashedUsers(page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve trash")
+		return
+	}
+
+	trashResponses := []models.TrashedUserResponse{}
+	for i := range users {
+		trashResponses = append(trashResponses, h.toTrashedResponse(c, &users[i]))
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "trash_retrieved",
+		"message": localize(c, "trash_retrieved", nil, ""),
+		"data":    trashResponses,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	})
+}
+
+// RestoreUser moves a user out of the trash (admin only)
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
+		return
+	}
+
+	user, err := h.users(c).RestoreUser(uint(userID))
+	if err != nil {
+		respondError(c, err, "Failed to restore user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "user_restored",
+		"message": localize(c, "user_restored", nil, ""),
+		"data":    toUserResponse(c, user),
+	})
+}
+
+// PurgeUser permanently deletes a trashed user after the retention period (admin only)
+func (h *UserHandler) PurgeUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
+		return
+	}
+
+	err = h.users(c).PurgeUser(uint(userID), h.cfg.Lifecycle.TrashRetention)
+	if err != nil {
+		respondError(c, err, "Failed to purge user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "user_purged",
+		"message": localize(c, "user_purged", nil, ""),
+	})
+}
+
+// PurgeExpiredTrash permanently deletes every user past the retention period (admin only)
+func (h *UserHandler) PurgeExpiredTrash(c *gin.Context) {
+	purged, err := h.users(c).PurgeExpiredUsers(h.cfg.Lifecycle.TrashRetention)
+	if err != nil {
+		respondError(c, err, "Failed to purge trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "expired_users_purged",
+		"message": localize(c, "expired_users_purged", nil, ""),
+		"count":   purged,
+	})
+}
+
+// toTrashedResponse converts a trashed user with its purge schedule
+func (h *UserHandler) toTrashedResponse(c *gin.Context, user *models.User) models.TrashedUserResponse {
+	return models.TrashedUserResponse{
+		UserResponse: toUserResponse(c, user),
+		DeletedAt:    user.DeletedAt.Time.In(location(c)),
+		PurgeAfter:   user.DeletedAt.Time.Add(h.cfg.Lifecycle.TrashRetention).In(location(c)),
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// ChangeMyPassword verifies the current password and sets a new one
+func (h *UserHandler) ChangeMyPassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	id := uint(userID.(int))
+	err := h.users(c).ChangePassword(id, req.CurrentPassword, req.NewPassword, h.cfg.Password.HistorySize)
+	if err != nil {
+		var policyErr *utils.PasswordPolicyError
+		if errors.As(err, &policyErr) || errors.Is(err, services.ErrValidation) {
+			// Help the user pick a better password
+			respondError(c, err, "Failed to change password", gin.H{
+				"strength": utils.EvaluatePasswordStrength(req.NewPassword).Localize(language(c)),
+			})
+			return
+		}
+		respondError(c, err, "Failed to change password")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "password_changed",
+		"message": localize(c, "password_changed", nil, ""),
+	})
+}
+
+// VerifyMyPassword re-authenticates the current user before a sensitive action
+func (h *UserHandler) VerifyMyPassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
+		return
+	}
+
+	var req models.VerifyPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	valid, err := h.users(c).VerifyPassword(uint(userID.(int)), req.Password)
+	if err != nil {
+		respondError(c, err, "Failed to verify password")
+		return
+	}
+
+	if !valid {
+		writeError(c, http.StatusForbidden, "incorrect_password", "password is incorrect")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "password_verified",
+		"message": localize(c, "password_verified", nil, ""),
+	})
+}
+
+// ResetUserPassword sets a temporary password that must be changed at next login (admin only)
+func (h *UserHandler) ResetUserPassword(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
+		return
+	}
+
+	temporaryPassword, err := h.users(c).ResetPassword(uint(userID), h.cfg.Password.TemporaryLength, h.cfg.Password.HistorySize)
+	if err != nil {
+		respondError(c, err, "Failed to reset password")
+		return
+	}
+
+	// The temporary password is only ever shown in this response
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"code":                 "password_reset",
+		"message":              localize(c, "password_reset", nil, ""),
+		"temporary_password":   temporaryPassword,
+		"must_change_password": true,
+	})
+}
+
+// CheckPasswordStrength gives live feedback on a candidate password
+func (h *UserHandler) CheckPasswordStrength(c *gin.Context) {
+	var req models.PasswordStrengthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "password_strength_evaluated",
+		"message": localize(c, "password_strength_evaluated", nil, ""),
+		"data":    utils.EvaluatePasswordStrength(req.Password).Localize(language(c)),
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// PatchMyProfile applies a JSON Merge Patch to the current user's profile
+func (h *UserHandler) PatchMyProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
+		return
+	}
+
+	h.patchUser(c, uint(userID.(int)), true, "profile_updated", "Failed to update profile")
+}
+
+// PatchUser applies a JSON Merge Patch to a user (admin only)
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
+		return
+	}
+
+	h.patchUser(c, uint(userID), false, "user_updated", "Failed to update user")
+}
+
+// patchUser parses, validates and applies a merge patch for userID.
+// Self-service patches are checked against the role's field policy.
+func (h *UserHandler) patchUser(c *gin.Context, userID uint, selfService bool, successCode, failureMessage string) {
+	patch, ok := h.bindMergePatch(c)
+	if !ok {
+		return
+	}
+
+	if selfService && !checkSelfServicePolicy(c, patch.Fields()) {
+		return
+	}
+
+	version, ok := requireIfMatch(c, userID)
+	if !ok {
+		return
+	}
+
+	user, err := h.users(c).PatchUser(userID, patch, version)
+	if err != nil {
+		respondUpdateError(c, userID, err, failureMessage)
+		return
+	}
+
+	c.Header("ETag", userETag(c, user))
+	c.JSON(http.StatusOK, gin.H{
+		"code":    successCode,
+		"message": localize(c, successCode, nil, ""),
+		"data":    toUserResponse(c, user),
+	})
+}
+
+// bindMergePatch reads an application/merge-patch+json body and validates it
+func (h *UserHandler) bindMergePatch(c *gin.Context) (*models.UserPatch, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		writeError(c, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be application/merge-patch+json")
+		return nil, false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return nil, false
+	}
+
+	patch, err := models.ParseUserPatch(body)
+	if err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format", gin.H{
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+
+	// Validate request (nulls are skipped by omitempty)
+	if err := h.validator.Struct(patch.Request); err != nil {
+		respondValidationError(c, err)
+		return nil, false
+	}
+
+	return patch, true
+}
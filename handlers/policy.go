@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+// checkSelfServicePolicy rejects self-service edits the caller's role may not make.
+// On rejection a 403 with per-field errors has been written.
+func checkSelfServicePolicy(c *gin.Context, fields []string) bool {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+
+	approval, denied := services.CheckSelfServiceFields(roleStr, fields)
+	for _, field := range approval {
+		denied[field] = services.FieldApproval
+	}
+
+	if len(denied) > 0 {
+		names := make([]string, 0, len(denied))
+		for field := range denied {
+			names = append(names, field)
+		}
+		sort.Strings(names)
+
+		fields := make([]services.FieldError, len(names))
+		for i, field := range names {
+			fields[i] = services.FieldError{
+				Field:   field,
+				Code:    string(denied[field]),
+				Message: field + " cannot be changed on your own profile",
+			}
+		}
+
+		extra := gin.H{"fields": localizeFields(c, fields, nil)}
+		if len(approval) > 0 {
+			extra["hint"] = localize(c, "self_service_hint", nil, "")
+		}
+		writeError(c, http.StatusForbidden, "self_service_denied", "Some fields cannot be changed on your own profile", extra)
+		return false
+	}
+
+	return true
+}
+
+// GetMyEditableFields returns the self-service field policy for the current user's role
+func (h *UserHandler) GetMyEditableFields(c *gin.Context) {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "editable_fields_retrieved",
+		"message": localize(c, "editable_fields_retrieved", nil, ""),
+		"role":    roleStr,
+		"data":    services.SelfServiceFields(roleStr),
+		"default": services.FieldReadOnly,
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+// queryBool parses an optional boolean query parameter
+func queryBool(c *gin.Context, key string) (*bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, services.FieldValidationError(key, "invalid", fmt.Sprintf("invalid %s: must be true or false", key))
+	}
+	return &b, nil
+}
+
+// queryTime parses an optional RFC 3339 or YYYY-MM-DD query parameter.
+// A bare date is midnight in the request's timezone.
+func queryTime(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, location(c)); err == nil {
+		return &t, nil
+	}
+	return nil, services.FieldValidationError(key, "invalid", fmt.Sprintf("invalid %s: expected RFC 3339 or YYYY-MM-DD", key))
+}
+
+// queryFields parses the fields= parameter against the UserResponse allowlist
+func queryFields(c *gin.Context) ([]string, error) {
+	value := c.Query("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if !models.IsUserResponseField(field) {
+			return nil, services.FieldValidationError("fields", "invalid", fmt.Sprintf("invalid field: %q", field))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// renderUsers converts users to responses, applying sparse field selection
+func renderUsers(c *gin.Context, users []models.User, fields []string) []interface{} {
+	data := make([]interface{}, 0, len(users))
+	for i := range users {
+		resp := toUserResponse(c, &users[i])
+		if fields != nil {
+			data = append(data, resp.Fields(fields))
+		} else {
+			data = append(data, resp)
+		}
+	}
+	return data
+}
+
+// nullableString returns nil for empty strings so they render as JSON null
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+type SetupHandler struct {
+	cfg              *config.Config
+	validator        *validator.Validate
+	bootstrapService *services.BootstrapService
+}
+
+func NewSetupHandler(cfg *config.Config, bootstrapService *services.BootstrapService) *SetupHandler {
+	return &SetupHandler{
+		cfg:              cfg,
+		validator:        newValidator(),
+		bootstrapService: bootstrapService,
+	}
+}
+
+// GetSetupStatus reports whether first-run setup is still pending
+func (h *SetupHandler) GetSetupStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    "setup_status_retrieved",
+		"message": localize(c, "setup_status_retrieved", nil, ""),
+		"data": gin.H{
+			"setup_required": h.bootstrapService.SetupRequired(),
+		},
+	})
+}
+
+// Setup creates the first admin using the one-time token printed at startup
+func (h *SetupHandler) Setup(c *gin.Context) {
+	var req models.SetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	user, err := h.bootstrapService.Setup(req.Token, &models.CreateUserRequest{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+		FullName: req.FullName,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to complete setup")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, gin.H{
+		"code":    "setup_completed",
+		"message": localize(c, "setup_completed", nil, ""),
+		"data":    toUserResponse(c, user),
+	})
+}
@@ -22,35 +22,41 @@ type UserHandler struct {
 func NewUserHandler(cfg *config.Config, userService *services.UserService) *UserHandler {
 	return &UserHandler{
 		cfg:         cfg,
-		validator:   validator.New(),
+		validator:   newValidator(),
 		userService: userService,
 	}
 }
 
+// users returns the user service bound to the request's context
+func (h *UserHandler) users(c *gin.Context) *services.UserService {
+	return h.userService.WithContext(c.Request.Context())
+}
+
 // GetMyProfile retrieves current user's complete profile
 func (h *UserHandler) GetMyProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User ID not found in context",
-		})
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
 		return
 	}
 
 	// Convert to uint (GORM uses uint for ID)
 	id := uint(userID.(int))
 
-	user, err := h.userService.GetUserByID(id)
+	user, err := h.users(c).GetUserByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "User not found",
-		})
+		respondError(c, err, "Failed to retrieve profile")
+		return
+	}
+
+	if setETag(c, user) {
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile retrieved successfully",
-		"data":    user.ToResponse(), // Remove sensitive fields
+		"code":    "profile_retrieved",
+		"message": localize(c, "profile_retrieved", nil, ""),
+		"data":    toUserResponse(c, user), // Remove sensitive fields
 	})
 }
 
@@ -58,41 +64,45 @@ func (h *UserHandler) GetMyProfile(c *gin.Context) {
 func (h *UserHandler) UpdateMyProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User ID not found in context",
-		})
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
 		return
 	}
 
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-		})
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
 		return
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"details": err.Error(),
-		})
+		respondValidationError(c, err)
+		return
+	}
+
+	// Only fields the role may edit on itself are accepted
+	if !checkSelfServicePolicy(c, req.Fields()) {
 		return
 	}
 
 	id := uint(userID.(int))
-	user, err := h.userService.UpdateUser(id, &req)
+	version, ok := requireIfMatch(c, id)
+	if !ok {
+		return
+	}
+
+	user, err := h.users(c).UpdateUser(id, &req, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update profile",
-		})
+		respondUpdateError(c, id, err, "Failed to update profile")
 		return
 	}
 
+	c.Header("ETag", userETag(c, user))
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
-		"data":    user.ToResponse(),
+		"code":    "profile_updated",
+		"message": localize(c, "profile_updated", nil, ""),
+		"data":    toUserResponse(c, user),
 	})
 }
 
@@ -101,32 +111,32 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid user ID",
-		})
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
 		return
 	}
 
-	user, err := h.userService.GetUserByID(uint(userID))
+	user, err := h.users(c).GetUserByID(uint(userID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "User not found",
-		})
+		respondError(c, err, "Failed to retrieve user")
+		return
+	}
+
+	if setETag(c, user) {
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "User profile retrieved successfully",
-		"data":    user.ToResponse(),
+		"code":    "user_profile_retrieved",
+		"message": localize(c, "user_profile_retrieved", nil, ""),
+		"data":    toUserResponse(c, user),
 	})
 }
 
-// GetAllUsers retrieves all users with pagination (admin only)
+// GetAllUsers retrieves all users with pagination, filters and sorting (admin only)
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	// Parse query parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	role := c.Query("role") // Optional role filter
 
 	if page < 1 {
 		page = 1
@@ -135,74 +145,178 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 		limit = 10
 	}
 
-	users, total, err := h.userService.GetAllUsers(page, limit, role)
+	filter, err := parseUserFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve users",
-		})
+		respondError(c, err, "Invalid filter")
 		return
 	}
 
-	// Convert to response format
-	var userResponses []models.UserResponse
-	for _, user := range users {
-		userResponses = append(userResponses, *user.ToResponse())
+	sort, err := services.ParseSort(c.Query("sort"))
+	if err != nil {
+		respondError(c, err, "Invalid sort")
+		return
 	}
 
-	// Calculate pagination info
-	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	fields, err := queryFields(c)
+	if err != nil {
+		respondError(c, err, "Invalid fields")
+		return
+	}
+
+	// Cursor mode is selected by the presence of ?cursor= (empty for the first page)
+	cursorStr, cursorMode := c.GetQuery("cursor")
+
+	defaultCount := services.CountExact
+	if cursorMode {
+		defaultCount = services.CountNone
+	}
+	countMode, err := services.ParseCountMode(c.Query("count"), defaultCount)
+	if err != nil {
+		respondError(c, err, "Invalid count")
+		return
+	}
+
+	// Reject a bad cursor before paying for the count
+	var cursor *services.Cursor
+	if cursorMode {
+		if cursorStr != "" {
+			if cursor, err = services.DecodeCursor(cursorStr); err != nil {
+				respondError(c, err, "Invalid cursor")
+				return
+			}
+		}
+		if err := services.ValidateCursorSort(sort); err != nil {
+			respondError(c, err, "Invalid sort")
+			return
+		}
+		if cursor != nil && cursor.Sort != services.SortSpec(sort) {
+			writeError(c, http.StatusBadRequest, "cursor_sort_mismatch", "Cursor does not match the requested sort")
+			return
+		}
+	}
+
+	total, err := h.users(c).CountUsers(filter, countMode)
+	if err != nil {
+		respondError(c, err, "Failed to count users")
+		return
+	}
+
+	pagination := gin.H{
+		"limit": limit,
+		"count": countMode,
+	}
+	if total != nil {
+		pagination["total"] = *total
+	}
+
+	var users []models.User
+	if cursorMode {
+		userPage, err := h.users(c).GetUsersByCursor(filter, sort, cursor, limit)
+		if err != nil {
+			respondError(c, err, "Failed to retrieve users")
+			return
+		}
+
+		users = userPage.Users
+		pagination["next_cursor"] = nullableString(userPage.NextCursor)
+		pagination["prev_cursor"] = nullableString(userPage.PrevCursor)
+	} else {
+		users, err = h.users(c).GetAllUsers(page, limit, filter, sort)
+		if err != nil {
+			respondError(c, err, "Failed to retrieve users")
+			return
+		}
+
+		pagination["page"] = page
+		if total != nil {
+			// Calculate pagination info
+			pagination["total_pages"] = int((*total + int64(limit) - 1) / int64(limit))
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Users retrieved successfully",
-		"data":    userResponses,
-		"pagination": gin.H{
-			"page":        page,
-			"limit":       limit,
-			"total":       total,
-			"total_pages": totalPages,
-		},
-		"filters": gin.H{
-			"role": role,
-		},
+		"code":       "users_retrieved",
+		"message":    localize(c, "users_retrieved", nil, ""),
+		"data":       renderUsers(c, users, fields),
+		"pagination": pagination,
+		"filters":    filter,
+		"sort":       services.SortSpec(sort),
 	})
 }
 
+// parseUserFilter reads the allowlisted listing filters from the query string
+func parseUserFilter(c *gin.Context) (services.UserFilter, error) {
+	filter := services.UserFilter{
+		Role:         c.Query("role"),
+		ClassLevel:   c.Query("class_level"),
+		AcademicYear: c.Query("academic_year"),
+		Status:       c.Query("status"),
+		Gender:       c.Query("gender"),
+	}
+
+	if filter.Role != "" && filter.Role != "admin" && filter.Role != "teacher" && filter.Role != "student" {
+		return filter, services.FieldValidationError("role", "invalid", "invalid role: must be admin, teacher or student")
+	}
+	if filter.Gender != "" && filter.Gender != "male" && filter.Gender != "female" {
+		return filter, services.FieldValidationError("gender", "invalid", "invalid gender: must be male or female")
+	}
+
+	var err error
+	if filter.IsActive, err = queryBool(c, "is_active"); err != nil {
+		return filter, err
+	}
+	if filter.HasPhoto, err = queryBool(c, "has_photo"); err != nil {
+		return filter, err
+	}
+	includeInactive, err := queryBool(c, "include_inactive")
+	if err != nil {
+		return filter, err
+	}
+	filter.IncludeInactive = includeInactive != nil && *includeInactive
+
+	if filter.CreatedFrom, err = queryTime(c, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = queryTime(c, "created_to"); err != nil {
+		return filter, err
+	}
+
+	// Optional enrollment year filter, e.g. 2024/2025 or 1445/1446H
+	if yearStr := c.Query("enrollment_year"); yearStr != "" {
+		year, err := utils.ParseAcademicYear(yearStr)
+		if err != nil {
+			return filter, services.FieldValidationError("enrollment_year", "invalid", err.Error())
+		}
+		filter.EnrollmentYear = &year
+	}
+
+	return filter, nil
+}
+
 // CreateUser creates a new user (admin only)
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-		})
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
 		return
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"details": err.Error(),
-		})
+		respondValidationError(c, err)
 		return
 	}
 
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.users(c).CreateUser(&req)
 	if err != nil {
-		if err.Error() == "username or email already exists" {
-			c.JSON(http.StatusConflict, gin.H{
-				"error": err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to create user",
-			})
-		}
+		respondError(c, err, "Failed to create user")
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "User created successfully",
-		"data":    user.ToResponse(),
+		"code":    "user_created",
+		"message": localize(c, "user_created", nil, ""),
+		"data":    toUserResponse(c, user),
 	})
 }
 
@@ -211,40 +325,39 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid user ID",
-		})
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
 		return
 	}
 
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request format",
-		})
+		writeError(c, http.StatusBadRequest, "invalid_request", "Invalid request format")
 		return
 	}
 
 	// Validate request
 	if err := h.validator.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Validation failed",
-			"details": err.Error(),
-		})
+		respondValidationError(c, err)
 		return
 	}
 
-	user, err := h.userService.UpdateUser(uint(userID), &req)
+	version, ok := requireIfMatch(c, uint(userID))
+	if !ok {
+		return
+	}
+
+	user, err := h.users(c).UpdateUser(uint(userID), &req, version)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update user",
-		})
+		respondUpdateError(c, uint(userID), err, "Failed to update user")
 		return
 	}
 
+	c.Header("ETag", userETag(c, user))
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "User updated successfully",
-		"data":    user.ToResponse(),
+		"code":    "user_updated",
+		"message": localize(c, "user_updated", nil, ""),
+		"data":    toUserResponse(c, user),
 	})
 }
 
@@ -253,48 +366,38 @@ func (h *UserHandler) DeactivateUser(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid user ID",
-		})
+		writeError(c, http.StatusBadRequest, "invalid_id", "Invalid user ID")
 		return
 	}
 
-	err = h.userService.DeactivateUser(uint(userID))
+	err = h.users(c).DeactivateUser(uint(userID))
 	if err != nil {
-		if err.Error() == "user not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "User not found",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to deactivate user",
-			})
-		}
+		respondError(c, err, "Failed to deactivate user")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "User deactivated successfully",
+		"code":    "user_deactivated",
+		"message": localize(c, "user_deactivated", nil, ""),
 	})
 }
 
 // GetTeachers retrieves all teachers
 func (h *UserHandler) GetTeachers(c *gin.Context) {
-	teachers, err := h.userService.GetTeachers()
+	teachers, err := h.users(c).GetTeachers()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve teachers",
-		})
+		respondError(c, err, "Failed to retrieve teachers")
 		return
 	}
 
 	var teacherResponses []models.UserResponse
 	for _, teacher := range teachers {
-		teacherResponses = append(teacherResponses, *teacher.ToResponse())
+		teacherResponses = append(teacherResponses, *toUserResponse(c, &teacher))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Teachers retrieved successfully",
+		"code":    "teachers_retrieved",
+		"message": localize(c, "teachers_retrieved", nil, ""),
 		"data":    teacherResponses,
 		"count":   len(teacherResponses),
 	})
@@ -302,21 +405,20 @@ func (h *UserHandler) GetTeachers(c *gin.Context) {
 
 // GetStudents retrieves all students
 func (h *UserHandler) GetStudents(c *gin.Context) {
-	students, err := h.userService.GetStudents()
+	students, err := h.users(c).GetStudents()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve students",
-		})
+		respondError(c, err, "Failed to retrieve students")
 		return
 	}
 
 	var studentResponses []models.UserResponse
 	for _, student := range students {
-		studentResponses = append(studentResponses, *student.ToResponse())
+		studentResponses = append(studentResponses, *toUserResponse(c, &student))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Students retrieved successfully",
+		"code":    "students_retrieved",
+		"message": localize(c, "students_retrieved", nil, ""),
 		"data":    studentResponses,
 		"count":   len(studentResponses),
 	})
@@ -326,27 +428,24 @@ func (h *UserHandler) GetStudents(c *gin.Context) {
 func (h *UserHandler) GetStudentsByClass(c *gin.Context) {
 	classLevel := c.Param("class")
 	if classLevel == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Class level is required",
-		})
+		writeError(c, http.StatusBadRequest, "class_level_required", "Class level is required")
 		return
 	}
 
-	students, err := h.userService.GetStudentsByClass(classLevel)
+	students, err := h.users(c).GetStudentsByClass(classLevel)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve students",
-		})
+		respondError(c, err, "Failed to retrieve students")
 		return
 	}
 
 	var studentResponses []models.UserResponse
 	for _, student := range students {
-		studentResponses = append(studentResponses, *student.ToResponse())
+		studentResponses = append(studentResponses, *toUserResponse(c, &student))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Students retrieved successfully",
+		"code":    "students_retrieved",
+		"message": localize(c, "students_retrieved", nil, ""),
 		"data":    studentResponses,
 		"class":   classLevel,
 		"count":   len(studentResponses),
@@ -355,16 +454,15 @@ func (h *UserHandler) GetStudentsByClass(c *gin.Context) {
 
 // GetClassList retrieves list of all classes
 func (h *UserHandler) GetClassList(c *gin.Context) {
-	classes, err := h.userService.GetClassList()
+	classes, err := h.users(c).GetClassList()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve class list",
-		})
+		respondError(c, err, "Failed to retrieve class list")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Class list retrieved successfully",
+		"code":    "class_list_retrieved",
+		"message": localize(c, "class_list_retrieved", nil, ""),
 		"data":    classes,
 		"count":   len(classes),
 	})
@@ -372,16 +470,15 @@ func (h *UserHandler) GetClassList(c *gin.Context) {
 
 // GetUserStats returns user statistics (admin only)
 func (h *UserHandler) GetUserStats(c *gin.Context) {
-	stats, err := h.userService.GetUserStats()
+	stats, err := h.users(c).GetUserStats()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve user statistics",
-		})
+		respondError(c, err, "Failed to retrieve user statistics")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "User statistics retrieved successfully",
+		"code":    "user_statistics_retrieved",
+		"message": localize(c, "user_statistics_retrieved", nil, ""),
 		"data":    stats,
 	})
 }
@@ -390,37 +487,53 @@ func (h *UserHandler) GetUserStats(c *gin.Context) {
 func (h *UserHandler) SearchUsers(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Search query is required",
-		})
+		writeError(c, http.StatusBadRequest, "query_required", "Search query is required")
 		return
 	}
 
 	role := c.Query("role")
+	classLevel := c.Query("class_level")
+	status := c.Query("status")
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 {
+		limit = 20
+	}
 	if limit > 100 {
 		limit = 100
 	}
 
-	users, err := h.userService.SearchUsers(query, role, limit)
+	results, err := h.users(c).SearchUsers(services.SearchOptions{
+		Query:      query,
+		Role:       role,
+		ClassLevel: classLevel,
+		Status:     status,
+		Limit:      limit,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to search users",
-		})
+		respondError(c, err, "Failed to search users")
 		return
 	}
 
-	var userResponses []models.UserResponse
-	for _, user := range users {
-		userResponses = append(userResponses, *user.ToResponse())
+	searchResponses := []models.UserSearchResult{}
+	for _, result := range results {
+		searchResponses = append(searchResponses, models.UserSearchResult{
+			UserResponse:  toUserResponse(c, &result.User),
+			Score:         result.Score,
+			MatchedFields: result.MatchedFields,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Search completed successfully",
-		"data":    userResponses,
+		"code":    "search_completed",
+		"message": localize(c, "search_completed", nil, ""),
+		"data":    searchResponses,
 		"query":   query,
-		"role":    role,
-		"count":   len(userResponses),
+		"filters": gin.H{
+			"role":        role,
+			"class_level": classLevel,
+			"status":      status,
+		},
+		"count": len(searchResponses),
 	})
 }
 
@@ -428,60 +541,41 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 func (h *UserHandler) UploadProfilePhoto(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User ID not found in context",
-		})
+		writeError(c, http.StatusUnauthorized, "unauthorized", "User ID not found in context")
 		return
 	}
 
 	file, err := c.FormFile("photo")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "No file uploaded",
-		})
+		writeError(c, http.StatusBadRequest, "file_required", "No file uploaded")
 		return
 	}
 
 	// Validate file
-	if err := utils.ValidateImageFile(file, h.cfg.Upload.MaxSize); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+	if err := utils.ValidateImageFile(file, h.cfg.Upload.MaxSize, h.cfg.Upload.AllowedFileTypes); err != nil {
+		writeError(c, http.StatusBadRequest, "invalid_file", err.Error())
 		return
 	}
 
 	// Save file
 	filename, err := utils.SaveUploadedFile(file, "profiles", userID.(int), h.cfg.Upload.Path)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to save file",
-		})
+		respondError(c, err, "Failed to save file")
 		return
 	}
 
 	// Update profile photo path in database
 	id := uint(userID.(int))
-	err = h.userService.UpdateUserPhoto(id, filename)
+	err = h.users(c).UpdateUserPhoto(id, filename)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update profile photo",
-		})
+		respondError(c, err, "Failed to update profile photo")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile photo updated successfully",
+		"code":    "profile_photo_updated",
+		"message": localize(c, "profile_photo_updated", nil, ""),
 		"photo":   filename,
 		"url":     "/files/" + filename,
 	})
 }
-
-// HealthCheck returns service health status
-func (h *UserHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":   "healthy",
-		"service":  h.cfg.ServiceName,
-		"version":  h.cfg.Version,
-		"database": "gorm+postgresql",
-	})
-}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the value of a sensitive attribute
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute, field and query parameter names whose values
+// are personal data or secrets and are never logged
+var sensitiveKeys = map[string]bool{
+	// Secrets
+	"password":           true,
+	"current_password":   true,
+	"new_password":       true,
+	"temporary_password": true,
+	"password_hash":      true,
+	"authorization":      true,
+	"cookie":             true,
+
+	// Personal data, much of it of minors
+	"username":           true,
+	"full_name":          true,
+	"student_id":         true,
+	"employee_id":        true,
+	"email":              true,
+	"phone":              true,
+	"address":            true,
+	"date_of_birth":      true,
+	"nisn":               true,
+	"salary":             true,
+	"parent_name":        true,
+	"parent_phone":       true,
+	"parent_email":       true,
+	"emergency_contact":  true,
+	"emergency_phone":    true,
+	"medical_conditions": true,
+
+	// Free-text search terms are usually names, emails or phone numbers
+	"q": true,
+}
+
+// opaqueKeys hold generated identifiers that are logged as they are, even
+// when they happen to look like a phone number
+var opaqueKeys = map[string]bool{
+	"request_id":  true,
+	"trace_id":    true,
+	"span_id":     true,
+	"setup_token": true,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\d(?:[ \-]?\d){9,}`) // 10 or more digits, e.g. 0812-3456-7890
+)
+
+// IsSensitive reports whether values named key must not be logged
+func IsSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// ScrubString masks email addresses and phone numbers in free text such as
+// error messages, which may quote the offending value
+func ScrubString(s string) string {
+	s = emailPattern.ReplaceAllString(s, Redacted)
+	return phonePattern.ReplaceAllString(s, Redacted)
+}
+
+// ScrubAttr is a slog ReplaceAttr function. It redacts sensitive attributes
+// and masks personal data in string and error values.
+func ScrubAttr(groups []string, a slog.Attr) slog.Attr {
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	if opaqueKeys[a.Key] {
+		return a
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, ScrubString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, ScrubString(err.Error()))
+		}
+		if m, ok := a.Value.Any().(map[string]interface{}); ok {
+			return slog.Any(a.Key, ScrubMap(m))
+		}
+	}
+	return a
+}
+
+// ScrubMap returns a copy of a decoded JSON payload with sensitive fields redacted
+func ScrubMap(m map[string]interface{}) map[string]interface{} {
+	scrubbed := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			scrubbed[k] = ScrubMap(value)
+		case string:
+			if IsSensitive(k) {
+				scrubbed[k] = Redacted
+			} else {
+				scrubbed[k] = ScrubString(value)
+			}
+		default:
+			if IsSensitive(k) {
+				scrubbed[k] = Redacted
+			} else {
+				scrubbed[k] = v
+			}
+		}
+	}
+	return scrubbed
+}
+
+// ScrubQuery returns a raw query string with sensitive parameters redacted
+func ScrubQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return Redacted
+	}
+	for key := range values {
+		if IsSensitive(key) {
+			values[key] = []string{Redacted}
+		}
+	}
+	return values.Encode()
+}
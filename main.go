@@ -3,117 +3,344 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+	_ "time/tzdata" // Timezone names work without the OS zoneinfo database
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"gitlab.com/nodiviti/user-service/config"
 	"gitlab.com/nodiviti/user-service/database"
 	"gitlab.com/nodiviti/user-service/handlers"
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/metrics"
 	"gitlab.com/nodiviti/user-service/middleware"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/ratelimit"
 	"gitlab.com/nodiviti/user-service/services"
+	"gitlab.com/nodiviti/user-service/tracing"
+	"gitlab.com/nodiviti/user-service/utils"
 )
 
 func main() {
-	// Load configuration
-	cfg := config.Load()
+	os.Exit(runCommand(os.Args[1:]))
+}
+
+// serve runs migrations, bootstraps the first admin and starts the HTTP server
+func serve(cfg *config.Config) {
+	// Structured logs on stdout
+	if _, err := logging.Setup(cfg.Logging, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
+	gin.DebugPrintRouteFunc = func(method, path, handler string, handlers int) {
+		slog.Debug("Route registered", "method", method, "path", path, "handler", handler)
+	}
+
+	// Start tracing before anything that creates spans
+	shutdownTracing, err := tracing.Init(cfg)
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
+	}
+	flushTraces := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("Failed to flush traces", "error", err)
+		}
+	}
+
+	// Apply password policy and hashing
+	if err := configurePasswords(cfg); err != nil {
+		fatal("Invalid password configuration", err)
+	}
 
 	// Initialize database with GORM
 	if err := database.InitDatabase(cfg); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to connect to database", err)
 	}
 
-	// Run auto-migrations (single users table)
-	if err := database.AutoMigrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// Connect to Redis if enabled
+	if err := database.InitRedis(cfg); err != nil {
+		fatal("Failed to connect to Redis", err)
 	}
 
-	// Seed initial admin user
-	if err := database.SeedData(); err != nil {
-		log.Fatalf("Failed to seed data: %v", err)
+	// Run auto-migrations (single users table)
+	if err := database.AutoMigrate(); err != nil {
+		fatal("Failed to run migrations", err)
 	}
 
 	// Create upload directory
 	if err := os.MkdirAll(cfg.Upload.Path, 0755); err != nil {
-		log.Fatalf("Failed to create upload directory: %v", err)
+		fatal("Failed to create upload directory", err)
 	}
 
-	// Setup graceful shutdown
+	// Liveness and readiness probes
+	healthHandler := handlers.NewHealthHandler(cfg)
+
+	// Signals received during startup are handled once the server runs
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	go func() {
-		<-quit
-		log.Println("🛑 Shutting down user service...")
-		database.Close()
-		os.Exit(0)
-	}()
-
 	// Initialize services
 	userService := services.NewUserService()
 
+	// Background workers, stopped in reverse order on shutdown
+	var stopWorkers []func()
+
+	// Purge users past the trash retention period
+	if cfg.Lifecycle.PurgeInterval > 0 {
+		stopWorkers = append(stopWorkers, userService.StartTrashSweeper(cfg.Lifecycle.PurgeInterval, cfg.Lifecycle.TrashRetention))
+	}
+
+	// Keep the user gauges of /metrics current
+	if cfg.Metrics.Enabled && cfg.Metrics.UserStatsInterval > 0 {
+		stopWorkers = append(stopWorkers, metrics.StartUserStatsRefresher(cfg.Metrics.UserStatsInterval, userService.GetUserStats))
+	}
+
+	changeRequestService := services.NewChangeRequestService(userService)
+	bootstrapService := services.NewBootstrapService(userService)
+
+	// Create the first admin on an empty database
+	admin, setupToken, err := bootstrapService.Bootstrap(cfg.Bootstrap)
+	if err != nil {
+		fatal("Failed to bootstrap admin user", err)
+	}
+	if admin != nil {
+		slog.Info("Bootstrap admin created", "username", admin.Username, "user_id", admin.ID)
+	}
+	if setupToken != "" {
+		slog.Warn("No users found. Create the first admin with POST /api/v1/setup", "setup_token", setupToken)
+	}
+
+	// Refuse to serve production traffic with a well-known password
+	defaultAccounts, err := bootstrapService.FindDefaultPasswordAccounts()
+	if err != nil {
+		fatal("Failed to check for default passwords", err)
+	}
+	if len(defaultAccounts) > 0 {
+		if cfg.GinMode == gin.ReleaseMode {
+			fatal("Refusing to start in release mode", fmt.Errorf("accounts %v still use the default password", defaultAccounts))
+		}
+		slog.Warn("Accounts still use the default password, change it before going to production", "accounts", defaultAccounts)
+	}
+
 	// Initialize handlers
+	if err := handlers.InitValidator(); err != nil {
+		fatal("Failed to initialize request validation", err)
+	}
 	userHandler := handlers.NewUserHandler(cfg, userService)
+	changeRequestHandler := handlers.NewChangeRequestHandler(cfg, changeRequestService)
+	setupHandler := handlers.NewSetupHandler(cfg, bootstrapService)
+	configHandler := handlers.NewConfigHandler(cfg)
 
 	// Setup routes
-	router := setupRoutes(userHandler, cfg)
+	router := setupRoutes(userHandler, changeRequestHandler, setupHandler, healthHandler, configHandler, userService, cfg)
 
 	// Start server
-	log.Printf("🚀 User Service starting on port %s", cfg.Port)
-	log.Println("📊 Database: PostgreSQL with GORM (Single users table)")
-	log.Println("📋 Features: Complete user management for all roles")
-	log.Println("👤 Initial Admin: admin@pesantren.com / Admin123!@#")
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
+	serverErr := make(chan error, 2)
+	go func() {
+		slog.Info("User Service starting", "port", cfg.Port, "version", cfg.Version)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// Prometheus metrics on their own listener, away from the public API
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.Path, metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:         cfg.Metrics.ListenAddress,
+			Handler:      mux,
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+		go func() {
+			slog.Info("Metrics listener starting", "address", cfg.Metrics.ListenAddress, "path", cfg.Metrics.Path)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		fatal("Failed to start server", err)
+	case sig := <-quit:
+		slog.Info("Shutting down user service", "signal", sig.String())
+	}
+
+	shutdown(cfg, server, metricsServer, healthHandler, stopWorkers)
+	flushTraces()
 }
 
-func setupRoutes(userHandler *handlers.UserHandler, cfg *config.Config) *gin.Engine {
-	router := gin.New()
+// shutdown stops taking traffic, drains in-flight requests, stops the metrics
+// listener and background workers and closes Redis and, last, the database
+func shutdown(cfg *config.Config, server, metricsServer *http.Server, healthHandler *handlers.HealthHandler, stopWorkers []func()) {
+	// Give load balancers time to see /readyz fail before the listener closes
+	healthHandler.SetShuttingDown()
+	time.Sleep(cfg.Server.ShutdownDelay)
 
-	// Middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("Failed to drain in-flight requests before the deadline", "error", err)
+		server.Close()
+	} else {
+		slog.Info("HTTP server stopped")
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			metricsServer.Close()
+		}
+	}
 
-	// CORS middleware
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+	for i := len(stopWorkers) - 1; i >= 0; i-- {
+		stopWorkers[i]()
+	}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	if err := database.CloseRedis(); err != nil {
+		slog.Warn("Failed to close Redis connection", "error", err)
+	}
+	if err := database.Close(); err != nil {
+		slog.Warn("Failed to close database connection", "error", err)
+	}
+}
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// configurePasswords applies the password policy, breached list and hasher from configuration
+func configurePasswords(cfg *config.Config) error {
+	utils.SetPasswordRequirements(utils.PasswordRequirementsFromConfig(cfg))
+
+	if cfg.Password.BreachedListPath != "" {
+		checker, err := utils.NewLocalBreachedPasswords(cfg.Password.BreachedListPath)
+		if err != nil {
+			return fmt.Errorf("failed to load breached password list: %v", err)
 		}
+		utils.SetBreachedPasswordChecker(checker)
+	}
 
-		c.Next()
-	})
+	hasher, err := utils.NewPasswordHasherFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	utils.SetPasswordHasher(hasher)
+	return nil
+}
 
-	// Serve static files
-	router.Static("/files", cfg.Upload.Path)
+func setupRoutes(userHandler *handlers.UserHandler, changeRequestHandler *handlers.ChangeRequestHandler, setupHandler *handlers.SetupHandler, healthHandler *handlers.HealthHandler, configHandler *handlers.ConfigHandler, userService *services.UserService, cfg *config.Config) *gin.Engine {
+	router := gin.New()
+
+	// Only listed proxies may set the client IP through X-Forwarded-For
+	if err := router.SetTrustedProxies(cfg.Security.TrustedProxies); err != nil {
+		fatal("Invalid trusted proxies", err)
+	}
+
+	// Probes are neither traced nor logged
+	quietPaths := map[string]bool{"/health": true, "/livez": true, "/readyz": true}
+
+	// Middleware: tracing first so request logs carry the trace ID. It runs
+	// even when nothing is exported, so an incoming traceparent still reaches
+	// the logs and the auth service.
+	router.Use(otelgin.Middleware(cfg.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !quietPaths[r.URL.Path]
+	})))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger("/health", "/livez", "/readyz"))
+	router.Use(middleware.Recovery())
+	router.Use(middleware.Language())
+	router.Use(middleware.Timezone(cfg.Location()))
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.Metrics())
+	}
+
+	// CORS and security headers
+	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+
+	// Serve static files; uploads may not run scripts in our origin
+	files := router.Group("/files", middleware.ContentSecurityPolicy(cfg.Security.FilesCSP))
+	files.Static("/", cfg.Upload.Path)
+
+	// Health checks; /health is kept for existing probes and reports readiness
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Readyz)
 
-	// Health check
-	router.GET("/health", userHandler.HealthCheck)
+	// Rate limits per route group, see config.RateLimitConfig
+	limit := func(string) gin.HandlerFunc { return func(c *gin.Context) { c.Next() } }
+	if cfg.RateLimit.Enabled {
+		store, err := ratelimit.NewStore(cfg.RateLimit.Store, database.GetRedis())
+		if err != nil {
+			fatal("Invalid rate limit configuration", err)
+		}
+		limit = func(group string) gin.HandlerFunc {
+			return middleware.RateLimit(store, group, cfg.RateLimit.Rules[group])
+		}
+	}
 
-	// API routes
+	// API routes; every request is limited by client IP before it costs an auth service call
 	api := router.Group("/api/v1")
+	api.Use(limit("ip"))
+
+	// Public routes
+	api.POST("/password/strength", userHandler.CheckPasswordStrength)
+
+	// First-run setup (only available until the first admin exists)
+	api.GET("/setup", setupHandler.GetSetupStatus)
+	api.POST("/setup", limit("password"), setupHandler.Setup)
 
 	// Protected routes (require authentication)
 	protected := api.Group("/")
-	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.AuthMiddleware(cfg), limit("user"))
+	// Users with a temporary password may only change it
+	protected.Use(middleware.RequirePasswordChange(func(ctx context.Context, userID uint) (*models.User, error) {
+		return userService.WithContext(ctx).GetAccountState(userID)
+	}, "POST /api/v1/users/me/password"))
+	// Timestamps in the caller's saved timezone unless X-Timezone names one
+	protected.Use(middleware.UserTimezone())
 	{
 		// My profile routes (all authenticated users)
 		users := protected.Group("/users")
 		{
 			users.GET("/me", userHandler.GetMyProfile)
 			users.PUT("/me", userHandler.UpdateMyProfile)
-			users.POST("/me/photo", userHandler.UploadProfilePhoto)
+			users.PATCH("/me", userHandler.PatchMyProfile)
+			users.GET("/me/editable-fields", userHandler.GetMyEditableFields)
+
+			// Profile change requests (fields that need approval)
+			users.POST("/me/change-requests", changeRequestHandler.SubmitMyChangeRequest)
+			users.GET("/me/change-requests", changeRequestHandler.GetMyChangeRequests)
+			users.DELETE("/me/change-requests/:id", changeRequestHandler.CancelMyChangeRequest)
+			users.POST("/me/photo", limit("upload"), userHandler.UploadProfilePhoto)
+			users.POST("/me/password", limit("password"), userHandler.ChangeMyPassword)
+			users.POST("/me/password/verify", limit("password"), userHandler.VerifyMyPassword)
 		}
 
 		// Admin/Teacher routes
@@ -125,6 +352,11 @@ func setupRoutes(userHandler *handlers.UserHandler, cfg *config.Config) *gin.Eng
 			adminTeacher.GET("/students", userHandler.GetStudents)
 			adminTeacher.GET("/students/class/:class", userHandler.GetStudentsByClass)
 			adminTeacher.GET("/classes", userHandler.GetClassList)
+
+			// Change request review (admins, or homeroom teachers for their class)
+			adminTeacher.GET("/change-requests", changeRequestHandler.GetChangeRequestQueue)
+			adminTeacher.POST("/change-requests/:id/approve", changeRequestHandler.ApproveChangeRequest)
+			adminTeacher.POST("/change-requests/:id/reject", changeRequestHandler.RejectChangeRequest)
 		}
 
 		// Admin only routes
@@ -134,9 +366,22 @@ func setupRoutes(userHandler *handlers.UserHandler, cfg *config.Config) *gin.Eng
 			admin.GET("/users", userHandler.GetAllUsers)
 			admin.POST("/users", userHandler.CreateUser) // Admin creates teachers/students
 			admin.PUT("/users/:id", userHandler.UpdateUser)
-			admin.DELETE("/users/:id", userHandler.DeactivateUser)
+			admin.PATCH("/users/:id", userHandler.PatchUser)
+			admin.POST("/users/:id/password/reset", userHandler.ResetUserPassword)
+
+			// User lifecycle: deactivate/activate keep the account, delete moves it to trash
+			admin.POST("/users/:id/deactivate", userHandler.DeactivateUser)
+			admin.POST("/users/:id/activate", userHandler.ActivateUser)
+			admin.DELETE("/users/:id", userHandler.DeleteUser)
+			admin.GET("/users/trash", userHandler.GetTrash)
+			admin.POST("/users/trash/:id/restore", userHandler.RestoreUser)
+			admin.DELETE("/users/trash/:id", userHandler.PurgeUser)
+			admin.POST("/users/trash/purge", userHandler.PurgeExpiredTrash)
 			admin.GET("/users/stats", userHandler.GetUserStats)
-			admin.GET("/search/users", userHandler.SearchUsers)
+			admin.GET("/search/users", limit("search"), userHandler.SearchUsers)
+
+			// Effective configuration, secrets redacted
+			admin.GET("/admin/config", configHandler.GetConfig)
 		}
 	}
 
@@ -0,0 +1,192 @@
+// user-service/metrics/metrics.go - Prometheus metrics
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "user_service"
+
+// Registry holds every metric of the service. It is separate from the
+// global registry so only what is registered here is exposed.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTP
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	// Database
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "GORM statement latency by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "GORM statements that failed, by operation and table. Record not found is not an error.",
+	}, []string{"operation", "table"})
+
+	// Auth service
+	AuthRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "auth_client",
+		Name:      "request_duration_seconds",
+		Help:      "Auth service call latency by operation and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "outcome"})
+
+	AuthRequestFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth_client",
+		Name:      "failures_total",
+		Help:      "Auth service calls that failed, by operation and reason.",
+	}, []string{"operation", "reason"})
+
+	// Rate limiting
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limit",
+		Name:      "rejections_total",
+		Help:      "Requests rejected with 429, by rate limit group.",
+	}, []string{"group"})
+
+	RateLimitErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limit",
+		Name:      "errors_total",
+		Help:      "Rate limit store failures, by group. Requests are let through when the store fails.",
+	}, []string{"group"})
+
+	// Users
+	ActiveUsers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "users",
+		Name:      "active",
+		Help:      "Active users by role.",
+	}, []string{"role"})
+
+	InactiveUsers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "users",
+		Name:      "inactive",
+		Help:      "Inactive users of every role.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		DBQueryDuration,
+		DBQueryErrors,
+		AuthRequestDuration,
+		AuthRequestFailures,
+		RateLimitRejections,
+		RateLimitErrors,
+		ActiveUsers,
+		InactiveUsers,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDBStats exposes the connection pool statistics of db
+func RegisterDBStats(db *sql.DB, name string) {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, name))
+	var already prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &already) {
+		slog.Warn("Failed to register connection pool metrics", "error", err)
+	}
+}
+
+// userStatsRoles maps GetUserStats keys to the role label of ActiveUsers
+var userStatsRoles = map[string]string{
+	"admins":   "admin",
+	"teachers": "teacher",
+	"students": "student",
+}
+
+// SetUserStats updates the user gauges from the counts of GetUserStats
+func SetUserStats(stats map[string]int64) {
+	for key, role := range userStatsRoles {
+		ActiveUsers.WithLabelValues(role).Set(float64(stats[key]))
+	}
+	InactiveUsers.Set(float64(stats["inactive"]))
+}
+
+// StartUserStatsRefresher updates the user gauges from stats every interval
+// until the returned stop function is called
+func StartUserStatsRefresher(interval time.Duration, stats func() (map[string]int64, error)) (stop func()) {
+	refresh := func() {
+		counts, err := stats()
+		if err != nil {
+			slog.Warn("Failed to refresh user metrics", "error", err)
+			return
+		}
+		SetUserStats(counts)
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		refresh()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
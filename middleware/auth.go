@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/logging"
 	"gitlab.com/nodiviti/user-service/utils"
 )
 
@@ -18,7 +19,8 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization header required",
+				"code":  "unauthorized",
+				"error": localize(c, "unauthorized", nil, "Authorization header required"),
 			})
 			c.Abort()
 			return
@@ -27,7 +29,8 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization header format",
+				"code":  "unauthorized",
+				"error": localize(c, "unauthorized", nil, "Invalid authorization header format"),
 			})
 			c.Abort()
 			return
@@ -36,10 +39,11 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		token := tokenParts[1]
 
 		// Validate token with auth service
-		authResp, err := authClient.ValidateToken(token)
+		authResp, err := authClient.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid token",
+				"code":    "invalid_token",
+				"error":   localize(c, "invalid_token", nil, "Invalid token"),
 				"details": err.Error(),
 			})
 			c.Abort()
@@ -48,7 +52,8 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 
 		if !authResp.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Token validation failed",
+				"code":  "invalid_token",
+				"error": localize(c, "invalid_token", nil, "Token validation failed"),
 			})
 			c.Abort()
 			return
@@ -60,6 +65,12 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Set("email", authResp.User.Email)
 		c.Set("role", authResp.User.Role)
 
+		// Identify the caller in every log record of this request
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(),
+			"user_id", authResp.User.ID,
+			"role", authResp.User.Role,
+		))
+
 		c.Next()
 	}
 }
@@ -70,7 +81,8 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 		role, exists := c.Get("role")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "User role not found in context",
+				"code":  "unauthorized",
+				"error": localize(c, "unauthorized", nil, "User role not found in context"),
 			})
 			c.Abort()
 			return
@@ -87,7 +99,8 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 		}
 
 		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Insufficient permissions",
+			"code":  "forbidden",
+			"error": localize(c, "forbidden", nil, "Insufficient permissions"),
 		})
 		c.Abort()
 	}
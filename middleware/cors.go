@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+// CORS answers preflight requests and adds CORS headers for the configured
+// origins. A wildcard origin is answered with "*" and never allows
+// credentials; listed origins are echoed back and may.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	allowAll := false
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// Not a cross-origin request
+		if origin == "" {
+			if c.Request.Method == http.MethodOptions {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// The answer depends on the origin unless every origin gets "*"
+		listed := origins[strings.ToLower(origin)]
+		if !allowAll || len(origins) > 1 {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+
+		switch {
+		case listed && origin != "*":
+			c.Header("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		case allowAll:
+			c.Header("Access-Control-Allow-Origin", "*")
+		default:
+			// Unknown origin: no CORS headers, so the browser blocks the response
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if exposed != "" {
+			c.Header("Access-Control-Expose-Headers", exposed)
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			if preflight {
+				c.Header("Access-Control-Allow-Methods", methods)
+				c.Header("Access-Control-Allow-Headers", headers)
+				if cfg.MaxAge > 0 {
+					c.Header("Access-Control-Max-Age", maxAge)
+				}
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
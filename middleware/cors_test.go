@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func corsRouter(cfg config.CORSConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string, preflight bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/users", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if preflight {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+var listedOrigins = config.CORSConfig{
+	AllowedOrigins:   []string{"https://app.example.com", "https://admin.example.com/"},
+	AllowedMethods:   []string{"GET", "PATCH"},
+	AllowedHeaders:   []string{"Authorization", "If-Match", "X-Timezone"},
+	ExposedHeaders:   []string{"ETag", "X-Timezone"},
+	AllowCredentials: true,
+	MaxAge:           10 * time.Minute,
+}
+
+func TestCORSPreflightAllowed(t *testing.T) {
+	w := corsRequest(corsRouter(listedOrigins), http.MethodOptions, "https://admin.example.com", true)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://admin.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, PATCH",
+		"Access-Control-Allow-Headers":     "Authorization, If-Match, X-Timezone",
+		"Access-Control-Max-Age":           "600",
+		"Vary":                             "Origin",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
+
+func TestCORSPreflightForbidden(t *testing.T) {
+	w := corsRequest(corsRouter(listedOrigins), http.MethodOptions, "https://evil.example.com", true)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+}
+
+func TestCORSSimpleRequests(t *testing.T) {
+	router := corsRouter(listedOrigins)
+
+	w := corsRequest(router, http.MethodGet, "https://app.example.com", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != "ETag, X-Timezone" {
+		t.Errorf("Access-Control-Expose-Headers = %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+
+	// Unknown origins still reach the handler, but without CORS headers
+	w = corsRequest(router, http.MethodGet, "https://evil.example.com", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("%s = %q for an unknown origin", header, got)
+		}
+	}
+
+	// Same-origin requests are left alone
+	w = corsRequest(router, http.MethodGet, "", false)
+	if got := w.Header().Get("Vary"); got != "" {
+		t.Errorf("Vary = %q without an Origin", got)
+	}
+}
+
+func TestCORSWildcardNeverAllowsCredentials(t *testing.T) {
+	cfg := listedOrigins
+	cfg.AllowedOrigins = []string{"*"}
+	w := corsRequest(corsRouter(cfg), http.MethodGet, "https://any.example.com", false)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q with a wildcard origin", got)
+	}
+	if got := w.Header().Get("Vary"); got != "" {
+		t.Errorf("Vary = %q, want none when every origin gets *", got)
+	}
+}
+
+func TestCORSWildcardWithListedOrigins(t *testing.T) {
+	cfg := listedOrigins
+	cfg.AllowedOrigins = []string{"*", "https://app.example.com"}
+	router := corsRouter(cfg)
+
+	w := corsRequest(router, http.MethodGet, "https://app.example.com", false)
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q for a listed origin", got)
+	}
+
+	w = corsRequest(router, http.MethodGet, "https://other.example.com", false)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q for an unlisted origin", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// Language picks the response language from the Accept-Language header and
+// stores it in the context as "lang"
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := utils.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		c.Set("lang", lang)
+		c.Header("Content-Language", string(lang))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
+
+// localize returns the message for code in the language chosen by Language,
+// or fallback when the catalog has none
+func localize(c *gin.Context, code string, params map[string]string, fallback string) string {
+	lang := utils.DefaultLanguage
+	if l, ok := c.Get("lang"); ok {
+		lang, _ = l.(utils.Language)
+	}
+	return utils.Localize(lang, code, params, fallback)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+// RequirePasswordChange blocks users holding a temporary password from an
+// admin reset (MustChangePassword) from every route but those in allowed,
+// given as "METHOD /full/path", until they choose a new password. account
+// loads the caller's row; it is stored in the context as "account".
+func RequirePasswordChange(account func(ctx context.Context, userID uint) (*models.User, error), allowed ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(allowed))
+	for _, route := range allowed {
+		exempt[route] = true
+	}
+
+	return func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		user, err := account(ctx, uint(userID.(int)))
+		if errors.Is(err, services.ErrNotFound) {
+			// Missing users are reported by the handlers
+			c.Next()
+			return
+		}
+		if err != nil {
+			// Fail closed: without the account state the gate cannot be checked
+			logging.FromContext(ctx).ErrorContext(ctx, "Failed to load account state", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"code":  "internal_error",
+				"error": localize(c, "internal_error", nil, "Failed to load account state"),
+			})
+			return
+		}
+		c.Set("account", user)
+
+		if user.MustChangePassword && !exempt[c.Request.Method+" "+c.FullPath()] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"code":  "password_change_required",
+				"error": localize(c, "password_change_required", nil, ""),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
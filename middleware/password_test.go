@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/services"
+)
+
+func passwordRouter(account func(ctx context.Context, userID uint) (*models.User, error)) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", 7) })
+	router.Use(RequirePasswordChange(account))
+	router.GET("/api/v1/users/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRequirePasswordChange(t *testing.T) {
+	cases := []struct {
+		name    string
+		account func(ctx context.Context, userID uint) (*models.User, error)
+		want    int
+	}{
+		{"regular account", func(context.Context, uint) (*models.User, error) { return &models.User{}, nil }, http.StatusOK},
+		{"temporary password", func(context.Context, uint) (*models.User, error) {
+			return &models.User{MustChangePassword: true}, nil
+		}, http.StatusForbidden},
+		{"missing account", func(context.Context, uint) (*models.User, error) {
+			return nil, services.NotFoundError("user", "user not found")
+		}, http.StatusOK},
+		{"lookup failure", func(context.Context, uint) (*models.User, error) {
+			return nil, errors.New("connection refused")
+		}, http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		if w := get(passwordRouter(tc.account), "/api/v1/users/me"); w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/metrics"
+	"gitlab.com/nodiviti/user-service/ratelimit"
+)
+
+// RateLimit takes a token from the group's bucket of the authenticated user,
+// or of the client IP before authentication. It sets the RateLimit-* headers
+// and answers 429 with Retry-After once the bucket is empty. If the store
+// fails the request is let through.
+func RateLimit(store ratelimit.Store, group string, rule config.RateLimitRule) gin.HandlerFunc {
+	if rule.Limit <= 0 || rule.Period <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	policy := fmt.Sprintf("%d;w=%d", rule.Limit, int64(rule.Period.Seconds()))
+
+	return func(c *gin.Context) {
+		key := "ratelimit:" + group + ":ip:" + c.ClientIP()
+		if userID, ok := c.Get("user_id"); ok {
+			key = fmt.Sprintf("ratelimit:%s:user:%v", group, userID)
+		}
+
+		ctx := c.Request.Context()
+		result, err := store.Take(ctx, key, rule)
+		if err != nil {
+			metrics.RateLimitErrors.WithLabelValues(group).Inc()
+			logging.FromContext(ctx).WarnContext(ctx, "Failed to check rate limit", "group", group, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			retryAfter := strconv.Itoa(ceilSeconds(result.RetryAfter))
+			metrics.RateLimitRejections.WithLabelValues(group).Inc()
+			c.Header("Retry-After", retryAfter)
+
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"code":  "rate_limited",
+				"error": localize(c, "rate_limited", map[string]string{"retry_after": retryAfter}, ""),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ceilSeconds rounds d up to whole seconds, as the headers require
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+const filesCSP = "default-src 'none'; sandbox"
+
+func securityRouter(cfg config.SecurityConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(SecurityHeaders(cfg))
+	router.GET("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+	files := router.Group("/files", ContentSecurityPolicy(cfg.FilesCSP))
+	files.GET("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	w := get(securityRouter(config.SecurityConfig{}), "/api/v1/users")
+
+	want := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"Referrer-Policy":        "no-referrer",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
+
+func TestHSTS(t *testing.T) {
+	w := get(securityRouter(config.SecurityConfig{}), "/api/v1/users")
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Strict-Transport-Security = %q when disabled", got)
+	}
+
+	w = get(securityRouter(config.SecurityConfig{HSTSMaxAge: 365 * 24 * time.Hour}), "/api/v1/users")
+	if got, want := w.Header().Get("Strict-Transport-Security"), "max-age=31536000; includeSubDomains"; got != want {
+		t.Errorf("Strict-Transport-Security = %q, want %q", got, want)
+	}
+}
+
+func TestContentSecurityPolicyOnFilesOnly(t *testing.T) {
+	router := securityRouter(config.SecurityConfig{FilesCSP: filesCSP})
+
+	if got := get(router, "/files/avatars/1.svg").Header().Get("Content-Security-Policy"); got != filesCSP {
+		t.Errorf("Content-Security-Policy on /files = %q, want %q", got, filesCSP)
+	}
+	if got := get(router, "/api/v1/users").Header().Get("Content-Security-Policy"); got != "" {
+		t.Errorf("Content-Security-Policy on the API = %q, want none", got)
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	router := gin.New()
+	if err := router.SetTrustedProxies([]string{"10.0.0.0/8"}); err != nil {
+		t.Fatal(err)
+	}
+	var clientIP string
+	router.GET("/", func(c *gin.Context) { clientIP = c.ClientIP() })
+
+	tests := []struct {
+		name   string
+		peer   string
+		header string
+		want   string
+	}{
+		{"trusted proxy", "10.1.2.3:4000", "203.0.113.7", "203.0.113.7"},
+		{"untrusted peer", "198.51.100.9:4000", "203.0.113.7", "198.51.100.9"},
+		{"no header", "10.1.2.3:4000", "", "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.peer
+		if tt.header != "" {
+			req.Header.Set("X-Forwarded-For", tt.header)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if clientIP != tt.want {
+			t.Errorf("%s: ClientIP = %q, want %q", tt.name, clientIP, tt.want)
+		}
+	}
+}
+
+func TestNoTrustedProxies(t *testing.T) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	var clientIP string
+	router.GET("/", func(c *gin.Context) { clientIP = c.ClientIP() })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.1.2.3:4000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if clientIP != "10.1.2.3" {
+		t.Errorf("ClientIP = %q, want the peer address", clientIP)
+	}
+}
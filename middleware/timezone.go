@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// TimezoneHeader names the IANA timezone a client wants timestamps in
+const TimezoneHeader = "X-Timezone"
+
+// Timezone picks the zone timestamps are rendered in and stores it in the
+// context as "tz": the X-Timezone header if it names a valid zone, else the
+// institution's. UserTimezone later applies the caller's saved preference.
+func Timezone(institution *time.Location) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		loc := institution
+		if name := c.GetHeader(TimezoneHeader); name != "" {
+			if requested, err := config.LoadTimezone(name); err == nil {
+				loc = requested
+				c.Set("tz_requested", true)
+			} else {
+				ctx := c.Request.Context()
+				logging.FromContext(ctx).DebugContext(ctx, "Ignoring invalid timezone header", "timezone", name)
+			}
+		}
+
+		setTimezone(c, loc)
+		c.Writer.Header().Add("Vary", TimezoneHeader)
+
+		c.Next()
+	}
+}
+
+// UserTimezone renders timestamps in the authenticated user's saved
+// timezone, unless the request asked for one. It reads the account loaded
+// by RequirePasswordChange, so it costs no query of its own.
+func UserTimezone() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := c.Get("account")
+		if c.GetBool("tz_requested") || !ok {
+			c.Next()
+			return
+		}
+
+		if user, _ := account.(*models.User); user != nil && user.Timezone != nil && *user.Timezone != "" {
+			if loc, err := config.LoadTimezone(*user.Timezone); err == nil {
+				setTimezone(c, loc)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// setTimezone stores loc in the context and reports it to the client
+func setTimezone(c *gin.Context, loc *time.Location) {
+	c.Set("tz", loc)
+	c.Header(TimezoneHeader, loc.String())
+}
@@ -0,0 +1,102 @@
+// user-service/models/date.go - Calendar dates without a time of day
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is how a Date is written in JSON, CSV and the database
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date such as a date of birth. It has no time of day or
+// zone, so it reads back as the same day whatever the server's, database's or
+// client's timezone. The embedded time is midnight UTC of that day.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the given calendar day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar day of t in t's own location
+func DateOf(t time.Time) Date {
+	return NewDate(t.Date())
+}
+
+// ParseDate parses YYYY-MM-DD. RFC 3339 timestamps are accepted for older
+// clients and keep the day as written, whatever their offset.
+func ParseDate(value string) (Date, error) {
+	if t, err := time.Parse(DateLayout, value); err == nil {
+		return DateOf(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return DateOf(t), nil
+	}
+	return Date{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", value)
+}
+
+// asTime returns the date as midnight UTC, or nil for a nil date
+func (d *Date) asTime() *time.Time {
+	if d == nil {
+		return nil
+	}
+	return &d.Time
+}
+
+// String returns the date as YYYY-MM-DD
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON writes the date as "YYYY-MM-DD"
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON reads a "YYYY-MM-DD" string, see ParseDate
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) || len(value) < 2 {
+		return fmt.Errorf("invalid date %s: expected a YYYY-MM-DD string", value)
+	}
+
+	parsed, err := ParseDate(value[1 : len(value)-1])
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Scan reads a date column. The driver returns it as midnight in some zone;
+// only its calendar day is kept.
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case string:
+		parsed, err := ParseDate(v)
+		*d = parsed
+		return err
+	case []byte:
+		parsed, err := ParseDate(string(v))
+		*d = parsed
+		return err
+	default:
+		return fmt.Errorf("cannot scan %T into a date", value)
+	}
+}
+
+// Value writes the date as text, so no timezone conversion applies
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,30 @@
+// user-service/models/password.go - Password management DTOs and history
+package models
+
+import (
+	"time"
+)
+
+// PasswordHistory keeps previous password hashes to block reuse
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UserID       uint      `json:"user_id" gorm:"not null;index"`
+	PasswordHash string    `json:"-" gorm:"size:255;not null"`
+}
+
+// ChangePasswordRequest is the body of POST /users/me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// VerifyPasswordRequest is the body of POST /users/me/password/verify
+type VerifyPasswordRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// PasswordStrengthRequest is the body of POST /password/strength
+type PasswordStrengthRequest struct {
+	Password string `json:"password" validate:"required"`
+}
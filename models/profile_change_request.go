@@ -0,0 +1,71 @@
+// user-service/models/profile_change_request.go - Self-service edits awaiting approval
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Change request statuses
+const (
+	ChangeRequestPending   = "pending"
+	ChangeRequestApproved  = "approved"
+	ChangeRequestRejected  = "rejected"
+	ChangeRequestCancelled = "cancelled"
+)
+
+// ProfileChangeRequest is a proposed change to a user's own profile
+type ProfileChangeRequest struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID      uint    `json:"user_id" gorm:"not null;index"`
+	Changes     string  `json:"-" gorm:"type:jsonb;not null"` // Proposed values, JSON object keyed by field
+	BaseVersion uint    `json:"base_version" gorm:"not null"` // User version when submitted
+	Status      string  `json:"status" gorm:"size:20;not null;default:'pending';index;check:status IN ('pending','approved','rejected','cancelled')"`
+	Note        *string `json:"note,omitempty" gorm:"type:text"` // Requester's explanation
+
+	ReviewedBy      *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	RejectionReason *string    `json:"rejection_reason,omitempty" gorm:"type:text"`
+
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// In renders the request's timestamps in loc
+func (r *ProfileChangeRequest) In(loc *time.Location) *ProfileChangeRequest {
+	r.CreatedAt = r.CreatedAt.In(loc)
+	r.UpdatedAt = r.UpdatedAt.In(loc)
+	if r.ReviewedAt != nil {
+		reviewedAt := r.ReviewedAt.In(loc)
+		r.ReviewedAt = &reviewedAt
+	}
+	return r
+}
+
+// SubmitChangeRequest is the body of a new change request.
+// Changes uses the same fields and validation as PatchUserRequest.
+type SubmitChangeRequest struct {
+	Changes json.RawMessage `json:"changes" validate:"required"`
+	Note    *string         `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// RejectChangeRequest is the body of a rejection
+type RejectChangeRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+// FieldChange is one field of a change request diffed against current values
+type FieldChange struct {
+	Field    string      `json:"field"`
+	Current  interface{} `json:"current"`
+	Proposed interface{} `json:"proposed"`
+}
+
+// ChangeRequestResponse is a change request with its diff
+type ChangeRequestResponse struct {
+	*ProfileChangeRequest
+	Requester *UserResponse `json:"requester,omitempty"`
+	Diff      []FieldChange `json:"diff"`
+}
@@ -0,0 +1,11 @@
+// user-service/models/setup.go - First-run setup DTOs
+package models
+
+// SetupRequest is the body of POST /setup, creating the first admin
+type SetupRequest struct {
+	Token    string  `json:"token" validate:"required"`
+	Username string  `json:"username" validate:"required,min=3,max=50"`
+	Email    string  `json:"email" validate:"required,email"`
+	Password string  `json:"password" validate:"required,min=8"`
+	FullName *string `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
+}
@@ -3,9 +3,14 @@ package models
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/utils"
 )
 
 // User represents complete user data in single table
@@ -13,47 +18,56 @@ type User struct {
 	ID        uint           `json:"id" gorm:"primarykey"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`                    // Soft delete (trash)
+	Version   uint           `json:"version" gorm:"not null;default:1"` // Optimistic concurrency (ETag)
 
 	// Auth fields (required)
-	Username     string `json:"username" gorm:"uniqueIndex;size:100;not null"`
-	Email        string `json:"email" gorm:"uniqueIndex;size:255;not null"`
+	Username     string `json:"username" gorm:"uniqueIndex:idx_users_username_live,where:deleted_at IS NULL;size:100;not null"`
+	Email        string `json:"email" gorm:"uniqueIndex:idx_users_email_live,where:deleted_at IS NULL;size:255;not null"`
 	PasswordHash string `json:"-" gorm:"size:255;not null"`
 	Role         string `json:"role" gorm:"size:20;not null;check:role IN ('admin','teacher','student')"`
 	IsActive     bool   `json:"is_active" gorm:"default:true;index"`
 
+	// Password lifecycle
+	MustChangePassword bool       `json:"must_change_password" gorm:"not null;default:false"` // Set by admin reset
+	PasswordChangedAt  *time.Time `json:"password_changed_at,omitempty"`
+	DefaultPassword    *bool      `json:"-" gorm:"index"` // Password is one of the seeded defaults; nil until checked
+
 	// Basic Profile fields (all optional)
-	FullName     *string    `json:"full_name,omitempty" gorm:"size:255"`
-	Phone        *string    `json:"phone,omitempty" gorm:"size:20"`
-	Address      *string    `json:"address,omitempty" gorm:"type:text"`
-	DateOfBirth  *time.Time `json:"date_of_birth,omitempty" gorm:"type:date"`
-	Gender       *string    `json:"gender,omitempty" gorm:"size:10;check:gender IN ('male','female')"`
-	ProfilePhoto *string    `json:"profile_photo,omitempty" gorm:"size:500"`
+	FullName     *string `json:"full_name,omitempty" gorm:"size:255"`
+	Phone        *string `json:"phone,omitempty" gorm:"size:20"`
+	Address      *string `json:"address,omitempty" gorm:"type:text"`
+	DateOfBirth  *Date   `json:"date_of_birth,omitempty" gorm:"type:date"`
+	Gender       *string `json:"gender,omitempty" gorm:"size:10;check:gender IN ('male','female')"`
+	ProfilePhoto *string `json:"profile_photo,omitempty" gorm:"size:500"`
 
 	// Role-specific fields (optional, depends on role)
 	// Teacher fields
-	EmployeeID      *string    `json:"employee_id,omitempty" gorm:"uniqueIndex;size:50"` // For teachers & admins
-	Specialization  *string    `json:"specialization,omitempty" gorm:"size:255"`         // For teachers
-	Qualification   *string    `json:"qualification,omitempty" gorm:"type:text"`         // For teachers
-	ExperienceYears *int       `json:"experience_years,omitempty" gorm:"default:0"`      // For teachers
-	HireDate        *time.Time `json:"hire_date,omitempty" gorm:"type:date"`             // For teachers & admins
-	Salary          *float64   `json:"salary,omitempty" gorm:"type:decimal(12,2)"`       // For teachers
+	EmployeeID      *string  `json:"employee_id,omitempty" gorm:"uniqueIndex:idx_users_employee_id_live,where:deleted_at IS NULL;size:50"` // For teachers & admins
+	Specialization  *string  `json:"specialization,omitempty" gorm:"size:255"`                                                             // For teachers
+	Qualification   *string  `json:"qualification,omitempty" gorm:"type:text"`                                                             // For teachers
+	ExperienceYears *int     `json:"experience_years,omitempty" gorm:"default:0"`                                                          // For teachers
+	HireDate        *Date    `json:"hire_date,omitempty" gorm:"type:date"`                                                                 // For teachers & admins
+	Salary          *float64 `json:"salary,omitempty" gorm:"type:decimal(12,2)"`                                                           // For teachers
+	HomeroomClass   *string  `json:"homeroom_class,omitempty" gorm:"size:50;index"`                                                        // Class a teacher is wali kelas of
 
 	// Student fields
-	StudentID      *string    `json:"student_id,omitempty" gorm:"uniqueIndex;size:50"` // For students
-	ClassLevel     *string    `json:"class_level,omitempty" gorm:"size:50"`            // For students
-	AcademicYear   *string    `json:"academic_year,omitempty" gorm:"size:20"`          // For students
-	ParentName     *string    `json:"parent_name,omitempty" gorm:"size:255"`           // For students
-	ParentPhone    *string    `json:"parent_phone,omitempty" gorm:"size:20"`           // For students
-	ParentEmail    *string    `json:"parent_email,omitempty" gorm:"size:255"`          // For students
-	EnrollmentDate *time.Time `json:"enrollment_date,omitempty" gorm:"type:date"`      // For students
-	GraduationDate *time.Time `json:"graduation_date,omitempty" gorm:"type:date"`      // For students
+	StudentID      *string `json:"student_id,omitempty" gorm:"uniqueIndex:idx_users_student_id_live,where:deleted_at IS NULL;size:50"` // For students
+	NISN           *string `json:"nisn,omitempty" gorm:"column:nisn;uniqueIndex:idx_users_nisn_live,where:deleted_at IS NULL;size:10"` // National student number
+	ClassLevel     *string `json:"class_level,omitempty" gorm:"size:50"`                                                               // For students
+	AcademicYear   *string `json:"academic_year,omitempty" gorm:"size:20"`                                                             // For students
+	ParentName     *string `json:"parent_name,omitempty" gorm:"size:255"`                                                              // For students
+	ParentPhone    *string `json:"parent_phone,omitempty" gorm:"size:20"`                                                              // For students
+	ParentEmail    *string `json:"parent_email,omitempty" gorm:"size:255"`                                                             // For students
+	EnrollmentDate *Date   `json:"enrollment_date,omitempty" gorm:"type:date"`                                                         // For students
+	GraduationDate *Date   `json:"graduation_date,omitempty" gorm:"type:date"`                                                         // For students
 
 	// Optional fields for all roles
 	EmergencyContact  *string `json:"emergency_contact,omitempty" gorm:"size:255"`
 	EmergencyPhone    *string `json:"emergency_phone,omitempty" gorm:"size:20"`
 	MedicalConditions *string `json:"medical_conditions,omitempty" gorm:"type:text"`
 	BloodType         *string `json:"blood_type,omitempty" gorm:"size:5"`
+	Timezone          *string `json:"timezone,omitempty" gorm:"size:64"` // IANA name timestamps are shown in; unset uses the institution's
 
 	// Status field (role-specific meaning)
 	Status *string `json:"status,omitempty" gorm:"size:20;default:'active'"` // active, inactive, graduated, etc
@@ -71,15 +85,16 @@ type CreateUserRequest struct {
 	Role     string `json:"role" validate:"required,oneof=admin teacher student"`
 
 	// Profile data (optional)
-	FullName    *string    `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
-	Phone       *string    `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	Address     *string    `json:"address,omitempty" validate:"omitempty,max=1000"`
-	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
-	Gender      *string    `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
+	FullName    *string `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
+	Phone       *string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
+	Address     *string `json:"address,omitempty" validate:"omitempty,max=1000"`
+	DateOfBirth *Date   `json:"date_of_birth,omitempty"`
+	Gender      *string `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
 
 	// Role-specific fields
 	EmployeeID     *string `json:"employee_id,omitempty"`
 	StudentID      *string `json:"student_id,omitempty"`
+	NISN           *string `json:"nisn,omitempty" validate:"omitempty,numeric,len=10"`
 	ClassLevel     *string `json:"class_level,omitempty"`
 	AcademicYear   *string `json:"academic_year,omitempty"`
 	ParentName     *string `json:"parent_name,omitempty"`
@@ -89,68 +104,129 @@ type CreateUserRequest struct {
 
 type UpdateUserRequest struct {
 	// Profile fields (all optional)
-	FullName    *string    `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
-	Phone       *string    `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	Address     *string    `json:"address,omitempty" validate:"omitempty,max=1000"`
-	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
-	Gender      *string    `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
+	FullName    *string `json:"full_name,omitempty" validate:"omitempty,min=2,max=255"`
+	Phone       *string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
+	Address     *string `json:"address,omitempty" validate:"omitempty,max=1000"`
+	DateOfBirth *Date   `json:"date_of_birth,omitempty"`
+	Gender      *string `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
 
 	// Role-specific updates
 	EmployeeID      *string `json:"employee_id,omitempty"`
 	StudentID       *string `json:"student_id,omitempty"`
+	NISN            *string `json:"nisn,omitempty" validate:"omitempty,numeric,len=10"`
 	ClassLevel      *string `json:"class_level,omitempty"`
 	AcademicYear    *string `json:"academic_year,omitempty"`
 	ParentName      *string `json:"parent_name,omitempty"`
 	ParentPhone     *string `json:"parent_phone,omitempty"`
+	ParentEmail     *string `json:"parent_email,omitempty" validate:"omitempty,email,max=255"`
+	EnrollmentDate  *Date   `json:"enrollment_date,omitempty"`
 	Specialization  *string `json:"specialization,omitempty"`
+	Qualification   *string `json:"qualification,omitempty" validate:"omitempty,max=1000"`
 	ExperienceYears *int    `json:"experience_years,omitempty"`
+	HireDate        *Date   `json:"hire_date,omitempty"`
+	HomeroomClass   *string `json:"homeroom_class,omitempty" validate:"omitempty,max=50"`
 
 	// Optional fields
 	EmergencyContact  *string `json:"emergency_contact,omitempty"`
 	EmergencyPhone    *string `json:"emergency_phone,omitempty"`
 	MedicalConditions *string `json:"medical_conditions,omitempty"`
+	BloodType         *string `json:"blood_type,omitempty" validate:"omitempty,oneof=A B AB O A+ A- B+ B- AB+ AB- O+ O-"`
+	Timezone          *string `json:"timezone,omitempty" validate:"omitempty,timezone"`
 	Status            *string `json:"status,omitempty"`
 }
 
+// Fields returns the JSON names of the fields set in the request
+func (r *UpdateUserRequest) Fields() []string {
+	var fields []string
+	v := reflect.ValueOf(r).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			fields = append(fields, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+		}
+	}
+	return fields
+}
+
 // UserResponse for API responses (without sensitive data)
 type UserResponse struct {
 	ID        uint      `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   uint      `json:"version"`
 
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Role     string `json:"role"`
 	IsActive bool   `json:"is_active"`
 
+	MustChangePassword bool       `json:"must_change_password"`
+	PasswordChangedAt  *time.Time `json:"password_changed_at,omitempty"`
+
 	// Profile data
-	FullName     *string    `json:"full_name,omitempty"`
-	Phone        *string    `json:"phone,omitempty"`
-	Address      *string    `json:"address,omitempty"`
-	DateOfBirth  *time.Time `json:"date_of_birth,omitempty"`
-	Gender       *string    `json:"gender,omitempty"`
-	ProfilePhoto *string    `json:"profile_photo,omitempty"`
+	FullName     *string `json:"full_name,omitempty"`
+	Phone        *string `json:"phone,omitempty"`
+	Address      *string `json:"address,omitempty"`
+	DateOfBirth  *Date   `json:"date_of_birth,omitempty"`
+	Gender       *string `json:"gender,omitempty"`
+	ProfilePhoto *string `json:"profile_photo,omitempty"`
 
 	// Role-specific data (based on role)
-	EmployeeID      *string    `json:"employee_id,omitempty"`
-	Specialization  *string    `json:"specialization,omitempty"`
-	Qualification   *string    `json:"qualification,omitempty"`
-	ExperienceYears *int       `json:"experience_years,omitempty"`
-	HireDate        *time.Time `json:"hire_date,omitempty"`
-
-	StudentID      *string    `json:"student_id,omitempty"`
-	ClassLevel     *string    `json:"class_level,omitempty"`
-	AcademicYear   *string    `json:"academic_year,omitempty"`
-	ParentName     *string    `json:"parent_name,omitempty"`
-	ParentPhone    *string    `json:"parent_phone,omitempty"`
-	ParentEmail    *string    `json:"parent_email,omitempty"`
-	EnrollmentDate *time.Time `json:"enrollment_date,omitempty"`
-	GraduationDate *time.Time `json:"graduation_date,omitempty"`
+	EmployeeID      *string `json:"employee_id,omitempty"`
+	Specialization  *string `json:"specialization,omitempty"`
+	Qualification   *string `json:"qualification,omitempty"`
+	ExperienceYears *int    `json:"experience_years,omitempty"`
+	HireDate        *Date   `json:"hire_date,omitempty"`
+	HomeroomClass   *string `json:"homeroom_class,omitempty"`
+
+	StudentID      *string `json:"student_id,omitempty"`
+	NISN           *string `json:"nisn,omitempty"`
+	ClassLevel     *string `json:"class_level,omitempty"`
+	AcademicYear   *string `json:"academic_year,omitempty"`
+	ParentName     *string `json:"parent_name,omitempty"`
+	ParentPhone    *string `json:"parent_phone,omitempty"`
+	ParentEmail    *string `json:"parent_email,omitempty"`
+	EnrollmentDate *Date   `json:"enrollment_date,omitempty"`
+	GraduationDate *Date   `json:"graduation_date,omitempty"`
 
 	EmergencyContact  *string `json:"emergency_contact,omitempty"`
 	EmergencyPhone    *string `json:"emergency_phone,omitempty"`
 	MedicalConditions *string `json:"medical_conditions,omitempty"`
+	Timezone          *string `json:"timezone,omitempty"`
 	Status            *string `json:"status,omitempty"`
+
+	// Hijri renderings of date fields (only when requested with ?calendar=hijri)
+	Hijri *HijriDates `json:"hijri,omitempty"`
+}
+
+// UserSearchResult is a ranked search hit with the fields that matched
+type UserSearchResult struct {
+	*UserResponse
+	Score         float64  `json:"score"`
+	MatchedFields []string `json:"matched_fields"`
+}
+
+// TrashedUserResponse is a user in the trash with its purge schedule
+type TrashedUserResponse struct {
+	*UserResponse
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+// HijriDates holds Hijri renderings of the date fields of a user
+type HijriDates struct {
+	CreatedAt      *HijriDateField `json:"created_at,omitempty"`
+	DateOfBirth    *HijriDateField `json:"date_of_birth,omitempty"`
+	HireDate       *HijriDateField `json:"hire_date,omitempty"`
+	EnrollmentDate *HijriDateField `json:"enrollment_date,omitempty"`
+	GraduationDate *HijriDateField `json:"graduation_date,omitempty"`
+}
+
+// HijriDateField is a single Hijri date with its formatted text
+type HijriDateField struct {
+	utils.HijriDate
+	Date      string `json:"date"`
+	Formatted string `json:"formatted"`
 }
 
 // Convert User to UserResponse (remove sensitive fields)
@@ -159,11 +235,15 @@ func (u *User) ToResponse() *UserResponse {
 		ID:        u.ID,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
+		Version:   u.Version,
 		Username:  u.Username,
 		Email:     u.Email,
 		Role:      u.Role,
 		IsActive:  u.IsActive,
 
+		MustChangePassword: u.MustChangePassword,
+		PasswordChangedAt:  u.PasswordChangedAt,
+
 		FullName:     u.FullName,
 		Phone:        u.Phone,
 		Address:      u.Address,
@@ -176,8 +256,10 @@ func (u *User) ToResponse() *UserResponse {
 		Qualification:   u.Qualification,
 		ExperienceYears: u.ExperienceYears,
 		HireDate:        u.HireDate,
+		HomeroomClass:   u.HomeroomClass,
 
 		StudentID:      u.StudentID,
+		NISN:           u.NISN,
 		ClassLevel:     u.ClassLevel,
 		AcademicYear:   u.AcademicYear,
 		ParentName:     u.ParentName,
@@ -189,10 +271,97 @@ func (u *User) ToResponse() *UserResponse {
 		EmergencyContact:  u.EmergencyContact,
 		EmergencyPhone:    u.EmergencyPhone,
 		MedicalConditions: u.MedicalConditions,
+		Timezone:          u.Timezone,
 		Status:            u.Status,
 	}
 }
 
+// userResponseFields maps UserResponse JSON names to struct field indexes
+var userResponseFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeOf(UserResponse{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+// IsUserResponseField reports whether name is a selectable UserResponse field
+func IsUserResponseField(name string) bool {
+	_, ok := userResponseFields[name]
+	return ok
+}
+
+// Fields returns a sparse response containing only the given fields.
+// Unset optional fields are returned as null rather than omitted.
+func (r *UserResponse) Fields(fields []string) map[string]interface{} {
+	v := reflect.ValueOf(r).Elem()
+	sparse := make(map[string]interface{}, len(fields))
+	for _, name := range fields {
+		if i, ok := userResponseFields[name]; ok {
+			sparse[name] = v.Field(i).Interface()
+		}
+	}
+	return sparse
+}
+
+// WithHijri adds Hijri renderings of the date fields to the response.
+// created_at is converted on its day in the response's timezone, see In.
+func (r *UserResponse) WithHijri() *UserResponse {
+	createdAt := r.CreatedAt
+	r.Hijri = &HijriDates{
+		CreatedAt:      toHijriField(&createdAt),
+		DateOfBirth:    toHijriField(r.DateOfBirth.asTime()),
+		HireDate:       toHijriField(r.HireDate.asTime()),
+		EnrollmentDate: toHijriField(r.EnrollmentDate.asTime()),
+		GraduationDate: toHijriField(r.GraduationDate.asTime()),
+	}
+	return r
+}
+
+// In renders the response's timestamps in loc. Dates are left as they are.
+func (r *UserResponse) In(loc *time.Location) *UserResponse {
+	r.CreatedAt = r.CreatedAt.In(loc)
+	r.UpdatedAt = r.UpdatedAt.In(loc)
+	if r.PasswordChangedAt != nil {
+		changedAt := r.PasswordChangedAt.In(loc)
+		r.PasswordChangedAt = &changedAt
+	}
+	return r
+}
+
+func toHijriField(t *time.Time) *HijriDateField {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+
+	date := utils.ToHijri(*t)
+	return &HijriDateField{
+		HijriDate: date,
+		Date:      date.String(),
+		Formatted: date.Format(),
+	}
+}
+
+// DiffUserResponse lists the response fields whose values differ between a and b
+func DiffUserResponse(a, b *UserResponse) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	changed := []string{}
+	for name, i := range userResponseFields {
+		if name == "updated_at" || name == "version" || name == "hijri" {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
 // Validation methods
 func (u *User) ValidateForRole() error {
 	switch u.Role {
@@ -0,0 +1,118 @@
+// user-service/models/user_patch.go - JSON Merge Patch (RFC 7396) for users
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PatchUserRequest lists the fields settable via JSON Merge Patch.
+// JSON names match the users table columns. An explicit null clears a field.
+type PatchUserRequest struct {
+	// Profile fields
+	FullName    *string `json:"full_name" validate:"omitempty,min=2,max=255"`
+	Phone       *string `json:"phone" validate:"omitempty,min=10,max=20"`
+	Address     *string `json:"address" validate:"omitempty,max=1000"`
+	DateOfBirth *Date   `json:"date_of_birth"`
+	Gender      *string `json:"gender" validate:"omitempty,oneof=male female"`
+
+	// Teacher fields
+	EmployeeID      *string `json:"employee_id" validate:"omitempty,max=50"`
+	Specialization  *string `json:"specialization" validate:"omitempty,max=255"`
+	Qualification   *string `json:"qualification" validate:"omitempty,max=1000"`
+	ExperienceYears *int    `json:"experience_years" validate:"omitempty,min=0,max=80"`
+	HireDate        *Date   `json:"hire_date"`
+	HomeroomClass   *string `json:"homeroom_class" validate:"omitempty,max=50"`
+
+	// Student fields
+	StudentID      *string `json:"student_id" validate:"omitempty,max=50"`
+	NISN           *string `json:"nisn" validate:"omitempty,numeric,len=10"`
+	ClassLevel     *string `json:"class_level" validate:"omitempty,max=50"`
+	AcademicYear   *string `json:"academic_year" validate:"omitempty,max=20"`
+	ParentName     *string `json:"parent_name" validate:"omitempty,max=255"`
+	ParentPhone    *string `json:"parent_phone" validate:"omitempty,min=10,max=20"`
+	ParentEmail    *string `json:"parent_email" validate:"omitempty,email,max=255"`
+	EnrollmentDate *Date   `json:"enrollment_date"`
+
+	// Optional fields
+	EmergencyContact  *string `json:"emergency_contact" validate:"omitempty,max=255"`
+	EmergencyPhone    *string `json:"emergency_phone" validate:"omitempty,min=10,max=20"`
+	MedicalConditions *string `json:"medical_conditions" validate:"omitempty,max=2000"`
+	BloodType         *string `json:"blood_type" validate:"omitempty,oneof=A B AB O A+ A- B+ B- AB+ AB- O+ O-"`
+	Timezone          *string `json:"timezone" validate:"omitempty,timezone"`
+	Status            *string `json:"status" validate:"omitempty,max=20"`
+}
+
+// UserPatch is a parsed merge patch: the typed request plus the keys it set.
+// Keys mapped to nil in Changes clear the column.
+type UserPatch struct {
+	Request PatchUserRequest
+	Changes map[string]interface{}
+}
+
+// ParseUserPatch parses a merge patch document.
+// Unknown fields and non-object documents are rejected.
+func ParseUserPatch(body []byte) (*UserPatch, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || raw == nil {
+		return nil, fmt.Errorf("merge patch must be a JSON object")
+	}
+
+	patch := &UserPatch{Changes: make(map[string]interface{}, len(raw))}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch.Request); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %v", err)
+	}
+
+	v := reflect.ValueOf(&patch.Request).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+
+		if string(bytes.TrimSpace(value)) == "null" {
+			patch.Changes[name] = nil
+		} else {
+			patch.Changes[name] = v.Field(i).Interface()
+		}
+	}
+
+	return patch, nil
+}
+
+// Fields returns the patched field names
+func (p *UserPatch) Fields() []string {
+	fields := make([]string, 0, len(p.Changes))
+	for name := range p.Changes {
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// ApplyTo applies the patch to a user in memory (used to validate the result)
+func (p *UserPatch) ApplyTo(u *User) {
+	v := reflect.ValueOf(u).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		value, ok := p.Changes[name]
+		if !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+		} else {
+			field.Set(reflect.ValueOf(value))
+		}
+	}
+}
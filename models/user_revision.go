@@ -0,0 +1,16 @@
+// user-service/models/user_revision.go - Per-version change log for users
+package models
+
+import (
+	"time"
+)
+
+// UserRevision records which fields changed in each version of a user.
+// It lets a 412 response tell the caller what changed since their version.
+type UserRevision struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at"`
+	UserID        uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_revisions_user_version"`
+	Version       uint      `json:"version" gorm:"not null;uniqueIndex:idx_user_revisions_user_version"`
+	ChangedFields string    `json:"changed_fields" gorm:"type:jsonb;not null"` // JSON array of field names
+}
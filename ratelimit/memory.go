@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+// sweepInterval is how often full, and so forgettable, buckets are dropped
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // When the bucket will be full again
+}
+
+// MemoryStore keeps buckets in process memory. Limits apply per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Take removes a token from the bucket of key if one is left
+func (s *MemoryStore) Take(ctx context.Context, key string, rule config.RateLimitRule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	rate := refillRate(rule)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Limit), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(rule.Limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(seconds((float64(rule.Limit) - b.tokens) / rate))
+
+	return newResult(rule, b.tokens, allowed), nil
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket
+// starts full anyway
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// user-service/ratelimit/ratelimit.go - Token bucket rate limiting
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+// Stores
+const (
+	StoreMemory = "memory"
+	StoreRedis  = "redis"
+)
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // Bucket capacity
+	Remaining  int           // Whole tokens left
+	RetryAfter time.Duration // Until the next token, when not allowed
+	Reset      time.Duration // Until the bucket is full again
+}
+
+// Store keeps token buckets. Each bucket holds up to rule.Limit tokens and
+// refills evenly over rule.Period, so bursts up to the limit are allowed.
+type Store interface {
+	Take(ctx context.Context, key string, rule config.RateLimitRule) (Result, error)
+}
+
+// NewStore returns the store named by name. The Redis store shares buckets
+// between instances and needs client.
+func NewStore(name string, client *redis.Client) (Store, error) {
+	switch name {
+	case StoreMemory, "":
+		return NewMemoryStore(), nil
+	case StoreRedis:
+		if client == nil {
+			return nil, fmt.Errorf("redis rate limit store needs REDIS_ENABLED=true")
+		}
+		return NewRedisStore(client), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q (want memory or redis)", name)
+	}
+}
+
+// refillRate is the number of tokens rule adds per second
+func refillRate(rule config.RateLimitRule) float64 {
+	return float64(rule.Limit) / rule.Period.Seconds()
+}
+
+// newResult describes a bucket left with tokens after a take
+func newResult(rule config.RateLimitRule, tokens float64, allowed bool) Result {
+	rate := refillRate(rule)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     rule.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(rule.Limit) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
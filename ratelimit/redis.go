@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+// takeScript refills and takes from a bucket atomically. It uses the Redis
+// clock so that instances with skewed clocks share buckets correctly. The
+// key expires once the bucket would be full again.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2]) -- tokens per millisecond
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis, so limits apply across instances
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Take removes a token from the bucket of key if one is left
+func (s *RedisStore) Take(ctx context.Context, key string, rule config.RateLimitRule) (Result, error) {
+	perMillisecond := refillRate(rule) / 1000
+	reply, err := takeScript.Run(ctx, s.client, []string{key}, rule.Limit, perMillisecond).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take rate limit token: %v", err)
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script reply: %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	text, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit token count %q: %v", text, err)
+	}
+
+	return newResult(rule, tokens, allowed == 1), nil
+}
@@ -0,0 +1,235 @@
+// user-service/services/bootstrap_service.go - First-run admin bootstrap
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/database"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// DefaultPasswords are passwords older releases seeded for the initial admin
+var DefaultPasswords = []string{"Admin123!@#"}
+
+// BootstrapService creates the first admin account on an empty database,
+// either from configuration or through a one-time setup token
+type BootstrapService struct {
+	db          *gorm.DB
+	userService *UserService
+
+	mu        sync.Mutex
+	tokenHash []byte // SHA-256 of the pending setup token, nil when setup is not available
+}
+
+func NewBootstrapService(userService *UserService) *BootstrapService {
+	return &BootstrapService{
+		db:          database.GetDB(),
+		userService: userService,
+	}
+}
+
+// Bootstrap runs on startup. If the users table is empty it creates the admin
+// from cfg, or, when no password is configured, issues a setup token. The token
+// is returned only here and is valid until setup completes or the process exits.
+func (s *BootstrapService) Bootstrap(cfg config.BootstrapConfig) (*models.User, string, error) {
+	var userCount int64
+	if err := s.db.Unscoped().Model(&models.User{}).Count(&userCount).Error; err != nil {
+		return nil, "", err
+	}
+	if userCount > 0 {
+		return nil, "", nil
+	}
+
+	password, err := bootstrapPassword(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if password != "" {
+		user, err := s.userService.CreateUser(&models.CreateUserRequest{
+			Username: cfg.AdminUsername,
+			Email:    cfg.AdminEmail,
+			Password: password,
+			Role:     "admin",
+			FullName: stringPtr("System Administrator"),
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create bootstrap admin: %w", err)
+		}
+		return user, "", nil
+	}
+
+	token, err := s.issueSetupToken()
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, token, nil
+}
+
+// SetupRequired reports whether a setup token is waiting to be used
+func (s *BootstrapService) SetupRequired() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenHash != nil
+}
+
+// Setup creates the first admin with the one-time setup token.
+// The token is consumed on success.
+func (s *BootstrapService) Setup(token string, req *models.CreateUserRequest) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tokenHash == nil {
+		return nil, &Error{Kind: ErrNotFound, Code: "setup_not_available", Message: "setup is not available"}
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare(sum[:], s.tokenHash) != 1 {
+		return nil, ForbiddenError("invalid_setup_token", "invalid setup token")
+	}
+
+	// Another instance may have completed setup in the meantime
+	var userCount int64
+	if err := s.db.Unscoped().Model(&models.User{}).Count(&userCount).Error; err != nil {
+		return nil, err
+	}
+	if userCount > 0 {
+		s.tokenHash = nil
+		return nil, &Error{Kind: ErrNotFound, Code: "setup_not_available", Message: "setup is not available"}
+	}
+
+	req.Role = "admin"
+	user, err := s.userService.CreateUser(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tokenHash = nil
+	return user, nil
+}
+
+// FindDefaultPasswordAccounts returns the usernames of every account that
+// still uses one of the DefaultPasswords. Accounts record this whenever their
+// password is set; accounts from before that are verified once, and the
+// result is stored so later starts only run a query.
+func (s *BootstrapService) FindDefaultPasswordAccounts() ([]string, error) {
+	if err := s.flagUncheckedPasswords(); err != nil {
+		return nil, err
+	}
+
+	var usernames []string
+	result := s.db.Model(&models.User{}).Where("default_password = ?", true).Order("username").Pluck("username", &usernames)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return usernames, nil
+}
+
+// flagUncheckedPasswords sets default_password on accounts that do not have
+// it yet. Verifying a hash is deliberately slow, so only ids and hashes are
+// loaded, a batch at a time, and each batch is checked on every CPU.
+func (s *BootstrapService) flagUncheckedPasswords() error {
+	var accounts []models.User
+	result := s.db.Unscoped().Select("id", "password_hash").Where("default_password IS NULL").
+		FindInBatches(&accounts, 500, func(tx *gorm.DB, batch int) error {
+			flags := make([]bool, len(accounts))
+			work := make(chan int)
+			var wg sync.WaitGroup
+			for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := range work {
+						flags[j] = usesDefaultPassword(accounts[j].PasswordHash)
+					}
+				}()
+			}
+			for j := range accounts {
+				work <- j
+			}
+			close(work)
+			wg.Wait()
+
+			ids := map[bool][]uint{}
+			for j, account := range accounts {
+				ids[flags[j]] = append(ids[flags[j]], account.ID)
+			}
+			for flag, flagged := range ids {
+				if err := s.db.Unscoped().Model(&models.User{}).Where("id IN ?", flagged).UpdateColumn("default_password", flag).Error; err != nil {
+					return fmt.Errorf("failed to record default password check: %v", err)
+				}
+			}
+			return nil
+		})
+	return result.Error
+}
+
+// usesDefaultPassword reports whether hash is of one of the DefaultPasswords
+func usesDefaultPassword(hash string) bool {
+	for _, password := range DefaultPasswords {
+		if match, _ := utils.CheckPasswordHash(password, hash); match {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefaultPassword reports whether a plain password is one of the DefaultPasswords
+func isDefaultPassword(password string) bool {
+	for _, p := range DefaultPasswords {
+		if subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// issueSetupToken generates a setup token and keeps only its hash
+func (s *BootstrapService) issueSetupToken() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate setup token: %v", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	sum := sha256.Sum256([]byte(token))
+	s.mu.Lock()
+	s.tokenHash = sum[:]
+	s.mu.Unlock()
+
+	return token, nil
+}
+
+// bootstrapPassword returns the configured admin password, preferring the secret file
+func bootstrapPassword(cfg config.BootstrapConfig) (string, error) {
+	if cfg.AdminPasswordFile != "" {
+		data, err := os.ReadFile(cfg.AdminPasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read bootstrap admin password file: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return cfg.AdminPassword, nil
+}
+
+// stringPtr returns a pointer to s
+func stringPtr(s string) *string {
+	return &s
+}
+
+// boolPtr returns a pointer to b
+func boolPtr(b bool) *bool {
+	return &b
+}
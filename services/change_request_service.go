@@ -0,0 +1,323 @@
+// user-service/services/change_request_service.go - Approval workflow for profile edits
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/database"
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// maxApproveAttempts bounds how often Approve rebases a request onto a newer
+// version before giving up on a user that keeps changing
+const maxApproveAttempts = 3
+
+type ChangeRequestService struct {
+	db          *gorm.DB
+	userService *UserService
+}
+
+func NewChangeRequestService(userService *UserService) *ChangeRequestService {
+	return &ChangeRequestService{
+		db:          database.GetDB(),
+		userService: userService,
+	}
+}
+
+// WithContext returns a copy of the service whose queries run with ctx
+func (s *ChangeRequestService) WithContext(ctx context.Context) *ChangeRequestService {
+	return &ChangeRequestService{db: s.db.WithContext(ctx), userService: s.userService.WithContext(ctx)}
+}
+
+// Submit creates a pending change request for the user's own profile.
+// Every field must be editable or approval-only for the user's role.
+func (s *ChangeRequestService) Submit(userID uint, role string, patch *models.UserPatch, note *string) (*models.ProfileChangeRequest, error) {
+	if len(patch.Changes) == 0 {
+		return nil, ValidationError("no changes requested")
+	}
+
+	for field, value := range patch.Changes {
+		if value == nil {
+			return nil, FieldValidationError(field, "not_clearable", field+" cannot be cleared through a change request")
+		}
+		if FieldAccessFor(role, field) == FieldReadOnly {
+			return nil, &Error{Kind: ErrForbidden, Code: "read_only_field", Field: field, Message: field + " is read-only"}
+		}
+	}
+
+	user, err := s.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := json.Marshal(patch.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	request := models.ProfileChangeRequest{
+		UserID:      userID,
+		Changes:     string(changes),
+		BaseVersion: user.Version,
+		Status:      models.ChangeRequestPending,
+		Note:        note,
+	}
+
+	result := s.db.Create(&request)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create change request: %v", result.Error)
+	}
+
+	return &request, nil
+}
+
+// ListForUser retrieves the change requests submitted by a user, newest first
+func (s *ChangeRequestService) ListForUser(userID uint) ([]models.ProfileChangeRequest, error) {
+	var requests []models.ProfileChangeRequest
+	result := s.db.Preload("User").Where("user_id = ?", userID).Order("created_at DESC").Find(&requests)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return requests, nil
+}
+
+// ListQueue retrieves change requests a reviewer may act on.
+// Admins see all; teachers see students of their homeroom class.
+func (s *ChangeRequestService) ListQueue(reviewerID uint, reviewerRole, status string) ([]models.ProfileChangeRequest, error) {
+	var requests []models.ProfileChangeRequest
+
+	query := s.db.Preload("User").
+		Joins("JOIN users ON users.id = profile_change_requests.user_id AND users.deleted_at IS NULL")
+
+	if status != "" {
+		query = query.Where("profile_change_requests.status = ?", status)
+	}
+
+	if reviewerRole != "admin" {
+		class, err := s.homeroomClass(reviewerID)
+		if err != nil {
+			return nil, err
+		}
+		if class == "" {
+			return []models.ProfileChangeRequest{}, nil
+		}
+		query = query.Where("users.role = ? AND users.class_level = ?", "student", class)
+	}
+
+	result := query.Order("profile_change_requests.created_at ASC").Find(&requests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return requests, nil
+}
+
+// Approve claims a pending change request and applies it through
+// UserService.UpdateUser in one transaction. Edits made after the request was
+// submitted only make it stale when they touch one of its fields; otherwise
+// it is applied on top of the current version, at most maxApproveAttempts
+// times.
+func (s *ChangeRequestService) Approve(requestID, reviewerID uint, reviewerRole string) (*models.ProfileChangeRequest, *models.User, error) {
+	request, err := s.getForReview(requestID, reviewerID, reviewerRole)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var update models.UpdateUserRequest
+	decoder := json.NewDecoder(bytes.NewReader([]byte(request.Changes)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		return nil, nil, fmt.Errorf("change request cannot be applied: %v", err)
+	}
+
+	var user *models.User
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.review(tx, request, reviewerID, models.ChangeRequestApproved, nil); err != nil {
+			return err
+		}
+
+		userService := &UserService{db: tx}
+		version := request.BaseVersion
+		for attempt := 1; ; attempt++ {
+			var err error
+			user, err = userService.UpdateUser(request.UserID, &update, version)
+
+			var conflict *VersionConflictError
+			if !errors.As(err, &conflict) {
+				return err
+			}
+			if overlap := overlappingFields(conflict.ChangedFields, update.Fields()); len(overlap) > 0 {
+				staleErr := StateError("change_request_stale", "profile was changed after the change request was submitted")
+				staleErr.Params = map[string]string{"fields": strings.Join(overlap, ", ")}
+				return staleErr
+			}
+			if attempt == maxApproveAttempts {
+				return StateError("concurrent_update", fmt.Sprintf("user %d kept changing while the change request was applied", request.UserID))
+			}
+			version = conflict.CurrentVersion
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return request, user, nil
+}
+
+// Reject closes a pending change request with a reason
+func (s *ChangeRequestService) Reject(requestID, reviewerID uint, reviewerRole, reason string) (*models.ProfileChangeRequest, error) {
+	request, err := s.getForReview(requestID, reviewerID, reviewerRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.review(s.db, request, reviewerID, models.ChangeRequestRejected, &reason); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// Cancel withdraws a pending change request by its requester
+func (s *ChangeRequestService) Cancel(requestID, userID uint) error {
+	result := s.db.Model(&models.ProfileChangeRequest{}).
+		Where("id = ? AND user_id = ? AND status = ?", requestID, userID, models.ChangeRequestPending).
+		Update("status", models.ChangeRequestCancelled)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return NotFoundError("change_request", "change request not found")
+	}
+
+	return nil
+}
+
+// Diff compares the proposed values of a request with the user's current values
+func (s *ChangeRequestService) Diff(request *models.ProfileChangeRequest) ([]models.FieldChange, error) {
+	var proposed map[string]interface{}
+	if err := json.Unmarshal([]byte(request.Changes), &proposed); err != nil {
+		return nil, fmt.Errorf("invalid change request data: %v", err)
+	}
+
+	fields := make([]string, 0, len(proposed))
+	for field := range proposed {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	current := request.User.ToResponse().Fields(fields)
+
+	diff := make([]models.FieldChange, 0, len(fields))
+	for _, field := range fields {
+		diff = append(diff, models.FieldChange{
+			Field:    field,
+			Current:  current[field],
+			Proposed: proposed[field],
+		})
+	}
+
+	return diff, nil
+}
+
+// overlappingFields returns the fields of changed that are also in requested
+func overlappingFields(changed, requested []string) []string {
+	wanted := make(map[string]bool, len(requested))
+	for _, field := range requested {
+		wanted[field] = true
+	}
+
+	overlap := []string{}
+	for _, field := range changed {
+		if wanted[field] {
+			overlap = append(overlap, field)
+		}
+	}
+	return overlap
+}
+
+// getForReview loads a pending request and checks the reviewer may act on it
+func (s *ChangeRequestService) getForReview(requestID, reviewerID uint, reviewerRole string) (*models.ProfileChangeRequest, error) {
+	var request models.ProfileChangeRequest
+	result := s.db.Preload("User").First(&request, requestID)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, NotFoundError("change_request", "change request not found")
+		}
+		return nil, result.Error
+	}
+
+	if request.Status != models.ChangeRequestPending {
+		return nil, StateError("change_request_closed", "change request is already "+request.Status)
+	}
+
+	if request.UserID == reviewerID {
+		return nil, ForbiddenError("own_change_request", "cannot review your own change request")
+	}
+
+	if reviewerRole != "admin" {
+		class, err := s.homeroomClass(reviewerID)
+		if err != nil {
+			return nil, err
+		}
+		student := request.User
+		if class == "" || student.Role != "student" || student.ClassLevel == nil || *student.ClassLevel != class {
+			return nil, ForbiddenError("review_not_allowed", "not allowed to review this change request")
+		}
+	}
+
+	return &request, nil
+}
+
+// review marks a pending request as approved or rejected. The status check
+// and update are one statement, so only one reviewer can claim a request.
+func (s *ChangeRequestService) review(db *gorm.DB, request *models.ProfileChangeRequest, reviewerID uint, status string, reason *string) error {
+	now := time.Now()
+	result := db.Model(request).
+		Where("status = ?", models.ChangeRequestPending).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"reviewed_by":      reviewerID,
+			"reviewed_at":      now,
+			"rejection_reason": reason,
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update change request: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return StateError("change_request_closed", "change request is no longer pending")
+	}
+
+	request.Status = status
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+	request.RejectionReason = reason
+	return nil
+}
+
+// homeroomClass returns the class a teacher is homeroom teacher of ("" if none)
+func (s *ChangeRequestService) homeroomClass(teacherID uint) (string, error) {
+	teacher, err := s.userService.GetUserByID(teacherID)
+	if err != nil {
+		return "", err
+	}
+	if teacher.Role != "teacher" || teacher.HomeroomClass == nil {
+		return "", nil
+	}
+	return *teacher.HomeroomClass, nil
+}
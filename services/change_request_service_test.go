@@ -0,0 +1,19 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverlappingFields(t *testing.T) {
+	changed := []string{"address", "phone", "profile_photo"}
+
+	if got := overlappingFields(changed, []string{"full_name", "gender"}); len(got) != 0 {
+		t.Errorf("unrelated fields = %v, want no overlap", got)
+	}
+
+	got := overlappingFields(changed, []string{"phone", "full_name"})
+	if want := []string{"phone"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("overlap = %v, want %v", got, want)
+	}
+}
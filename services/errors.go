@@ -0,0 +1,104 @@
+// user-service/services/errors.go - Typed domain errors
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Error kinds. Match them with errors.Is; the handlers map each kind to one
+// HTTP status.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrForbidden  = errors.New("forbidden")
+)
+
+// FieldError describes a problem with one request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error with a stable machine-readable code.
+// Message is safe to show to API clients.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Field   string            // The conflicting field of a Conflict
+	Fields  []FieldError      // Per-field details of a Validation error
+	Params  map[string]string // Placeholder values of the code's localized message
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NotFoundError reports a missing resource, e.g. NotFoundError("user", "user not found")
+func NotFoundError(resource, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: resource + "_not_found", Message: message}
+}
+
+// ConflictError reports a unique field already taken by another record
+func ConflictError(field, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: "conflict", Field: field, Message: message}
+}
+
+// StateError reports an operation the resource's current state does not allow
+func StateError(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// ValidationError reports invalid input, optionally with per-field details
+func ValidationError(message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: "validation_failed", Message: message, Fields: fields}
+}
+
+// FieldValidationError reports invalid input in a single field
+func FieldValidationError(field, code, message string) *Error {
+	return ValidationError(message, FieldError{Field: field, Code: code, Message: message})
+}
+
+// ForbiddenError reports an action the caller may not perform
+func ForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+// AsError returns the domain error in err's chain, if any
+func AsError(err error) (*Error, bool) {
+	var domainErr *Error
+	ok := errors.As(err, &domainErr)
+	return domainErr, ok
+}
+
+// uniqueConstraintFields maps unique indexes to the field they protect
+var uniqueConstraintFields = map[string]string{
+	"idx_users_username_live":    "username",
+	"idx_users_email_live":       "email",
+	"idx_users_employee_id_live": "employee_id",
+	"idx_users_student_id_live":  "student_id",
+	"idx_users_nisn_live":        "nisn",
+}
+
+// translateDBError turns Postgres unique violations into Conflict errors, so a
+// lost race with a concurrent insert reports the field instead of a 500.
+// Other errors are wrapped with context.
+func translateDBError(err error, context string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if field, ok := uniqueConstraintFields[pgErr.ConstraintName]; ok {
+			return ConflictError(field, fmt.Sprintf("%s is already used by another user", field))
+		}
+		return ConflictError("", "record already exists")
+	}
+	return fmt.Errorf("%s: %v", context, err)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTranslateDBError(t *testing.T) {
+	violation := fmt.Errorf("restore: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email_live"})
+	err := translateDBError(violation, "failed to restore user")
+	domainErr, ok := AsError(err)
+	if !ok || !errors.Is(err, ErrConflict) || domainErr.Field != "email" {
+		t.Fatalf("unique violation = %#v, want an email conflict", err)
+	}
+
+	err = translateDBError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_other"}, "failed to restore user")
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("unknown unique violation = %v, want a conflict", err)
+	}
+
+	err = translateDBError(errors.New("connection reset"), "failed to restore user")
+	if _, ok := AsError(err); ok || err.Error() != "failed to restore user: connection reset" {
+		t.Errorf("other error = %v, want it wrapped with context", err)
+	}
+}
@@ -0,0 +1,168 @@
+// user-service/services/password_service.go - Password change, reset and history
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/logging"
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// ChangePassword verifies the current password and sets a new one.
+// The new password may not match the current one or the last historySize.
+func (s *UserService) ChangePassword(userID uint, currentPassword, newPassword string, historySize int) error {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if match, _ := utils.CheckPasswordHash(currentPassword, user.PasswordHash); !match {
+		return ForbiddenError("incorrect_password", "current password is incorrect")
+	}
+
+	if err := utils.ValidatePasswordForUser(newPassword, userPasswordContext(user)); err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordHistory(user, newPassword, historySize); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.setPassword(user, hashedPassword, isDefaultPassword(newPassword), false, historySize)
+}
+
+// VerifyPassword checks a user's password. When the stored hash uses an
+// outdated algorithm or parameters it is transparently replaced with a hash
+// from the current hasher; a failed upgrade is logged but does not fail the check.
+func (s *UserService) VerifyPassword(userID uint, password string) (bool, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	match, needsRehash := utils.CheckPasswordHash(password, user.PasswordHash)
+	if !match {
+		return false, nil
+	}
+
+	if needsRehash {
+		if err := s.rehashPassword(user, password); err != nil {
+			ctx := s.db.Statement.Context
+			logging.FromContext(ctx).WarnContext(ctx, "Failed to upgrade password hash", "target_user_id", user.ID, "error", err)
+		}
+	}
+
+	return true, nil
+}
+
+// rehashPassword stores a new hash of an unchanged password. It is not a
+// profile change, so the version is left alone; the update is skipped if the
+// password was changed concurrently.
+func (s *UserService) rehashPassword(user *models.User, password string) error {
+	hashedPassword, err := utils.HashPasswordUnvalidated(password)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Model(&models.User{}).
+		Where("id = ? AND password_hash = ?", user.ID, user.PasswordHash).
+		UpdateColumn("password_hash", hashedPassword).Error
+}
+
+// ResetPassword sets a generated temporary password that must be changed at next login
+func (s *UserService) ResetPassword(userID uint, length, historySize int) (string, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	temporaryPassword, err := utils.GenerateTemporaryPassword(length)
+	if err != nil {
+		return "", err
+	}
+
+	// Generated, not chosen, so the policy is not the admin's to satisfy
+	hashedPassword, err := utils.HashPasswordUnvalidated(temporaryPassword)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.setPassword(user, hashedPassword, false, true, historySize); err != nil {
+		return "", err
+	}
+
+	return temporaryPassword, nil
+}
+
+// userPasswordContext returns the personal information of an existing user
+func userPasswordContext(user *models.User) utils.PasswordContext {
+	ctx := utils.PasswordContext{Username: user.Username, Email: user.Email}
+	if user.FullName != nil {
+		ctx.FullName = *user.FullName
+	}
+	return ctx
+}
+
+// checkPasswordHistory rejects reuse of the current or recent passwords
+func (s *UserService) checkPasswordHistory(user *models.User, password string, historySize int) error {
+	if match, _ := utils.CheckPasswordHash(password, user.PasswordHash); match {
+		return FieldValidationError("new_password", "password_reused", "new password must be different from the current password")
+	}
+
+	if historySize <= 0 {
+		return nil
+	}
+
+	var history []models.PasswordHistory
+	result := s.db.Where("user_id = ?", user.ID).Order("created_at DESC, id DESC").Limit(historySize).Find(&history)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, entry := range history {
+		if match, _ := utils.CheckPasswordHash(password, entry.PasswordHash); match {
+			return FieldValidationError("new_password", "password_reused", "password was used recently, choose a different one")
+		}
+	}
+
+	return nil
+}
+
+// setPassword stores a new hash, archives the old one and trims the history.
+// defaultPassword records whether the new password is one of the
+// DefaultPasswords. must_change_password and password_changed_at are part of
+// the user representation, so the change bumps the version and records a
+// revision.
+func (s *UserService) setPassword(user *models.User, hashedPassword string, defaultPassword, mustChange bool, historySize int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if historySize > 0 {
+			if err := tx.Create(&models.PasswordHistory{UserID: user.ID, PasswordHash: user.PasswordHash}).Error; err != nil {
+				return fmt.Errorf("failed to record password history: %v", err)
+			}
+
+			// Keep only the most recent historySize entries
+			keep := tx.Model(&models.PasswordHistory{}).Select("id").
+				Where("user_id = ?", user.ID).Order("created_at DESC, id DESC").Limit(historySize)
+			if err := tx.Where("user_id = ? AND id NOT IN (?)", user.ID, keep).Delete(&models.PasswordHistory{}).Error; err != nil {
+				return fmt.Errorf("failed to trim password history: %v", err)
+			}
+		}
+
+		var updated models.User
+		return applyUpdateTx(tx, &updated, user.ID, 0, map[string]interface{}{
+			"password_hash":        hashedPassword,
+			"default_password":     defaultPassword,
+			"must_change_password": mustChange,
+			"password_changed_at":  time.Now(),
+		})
+	})
+}
@@ -0,0 +1,104 @@
+// user-service/services/profile_policy.go - Which fields users may edit on themselves
+package services
+
+import (
+	"sort"
+)
+
+// FieldAccess is how a user may change one of their own profile fields
+type FieldAccess string
+
+const (
+	FieldEditable FieldAccess = "editable"          // applied immediately
+	FieldApproval FieldAccess = "requires_approval" // needs an admin to approve
+	FieldReadOnly FieldAccess = "read_only"         // only admins may change it
+)
+
+// SelfServicePolicy declares, per role, how users may edit their own fields.
+// Fields not listed for a role are read-only.
+var SelfServicePolicy = map[string]map[string]FieldAccess{
+	"student": {
+		"phone":              FieldEditable,
+		"address":            FieldEditable,
+		"emergency_contact":  FieldEditable,
+		"emergency_phone":    FieldEditable,
+		"medical_conditions": FieldEditable,
+		"blood_type":         FieldEditable,
+		"timezone":           FieldEditable,
+
+		"full_name":     FieldApproval,
+		"date_of_birth": FieldApproval,
+		"gender":        FieldApproval,
+		"parent_name":   FieldApproval,
+		"parent_phone":  FieldApproval,
+		"parent_email":  FieldApproval,
+	},
+	"teacher": {
+		"phone":              FieldEditable,
+		"address":            FieldEditable,
+		"emergency_contact":  FieldEditable,
+		"emergency_phone":    FieldEditable,
+		"medical_conditions": FieldEditable,
+		"blood_type":         FieldEditable,
+		"timezone":           FieldEditable,
+		"qualification":      FieldEditable,
+
+		"full_name":        FieldApproval,
+		"date_of_birth":    FieldApproval,
+		"gender":           FieldApproval,
+		"specialization":   FieldApproval,
+		"experience_years": FieldApproval,
+	},
+	"admin": {
+		"full_name":          FieldEditable,
+		"phone":              FieldEditable,
+		"address":            FieldEditable,
+		"date_of_birth":      FieldEditable,
+		"gender":             FieldEditable,
+		"employee_id":        FieldEditable,
+		"qualification":      FieldEditable,
+		"hire_date":          FieldEditable,
+		"emergency_contact":  FieldEditable,
+		"emergency_phone":    FieldEditable,
+		"medical_conditions": FieldEditable,
+		"blood_type":         FieldEditable,
+		"timezone":           FieldEditable,
+	},
+}
+
+// FieldAccessFor returns how a user with role may edit field on themselves
+func FieldAccessFor(role, field string) FieldAccess {
+	if access, ok := SelfServicePolicy[role][field]; ok {
+		return access
+	}
+	return FieldReadOnly
+}
+
+// SelfServiceFields returns the policy of a role, field name to access
+func SelfServiceFields(role string) map[string]FieldAccess {
+	fields := make(map[string]FieldAccess, len(SelfServicePolicy[role]))
+	for field, access := range SelfServicePolicy[role] {
+		fields[field] = access
+	}
+	return fields
+}
+
+// CheckSelfServiceFields splits the given fields by access for role.
+// It returns the fields that need approval and the fields that are not
+// editable at all (mapped to their access level).
+func CheckSelfServiceFields(role string, fields []string) (approval []string, denied map[string]FieldAccess) {
+	denied = make(map[string]FieldAccess)
+
+	for _, field := range fields {
+		switch access := FieldAccessFor(role, field); access {
+		case FieldEditable:
+		case FieldApproval:
+			approval = append(approval, field)
+		default:
+			denied[field] = access
+		}
+	}
+
+	sort.Strings(approval)
+	return approval, denied
+}
@@ -0,0 +1,208 @@
+// user-service/services/promotion.go - End-of-year class promotion
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// PromotionOptions controls an end-of-year promotion
+type PromotionOptions struct {
+	FromYear    string            // Only students of this academic year are promoted
+	ToYear      string            // Academic year given to promoted students
+	FinalGrade  int               // Students in this grade graduate instead of moving up
+	GraduatedOn models.Date       // Graduation date recorded for graduates
+	Classes     map[string]string // Explicit class mapping; an empty target graduates the class
+	DryRun      bool
+}
+
+// ClassPromotion is the outcome for one class
+type ClassPromotion struct {
+	From      string `json:"from"`
+	To        string `json:"to,omitempty"`
+	Graduates bool   `json:"graduates"`
+	Students  int    `json:"students"`
+}
+
+// PromotionReport summarises a promotion
+type PromotionReport struct {
+	FromYear    string           `json:"from_year,omitempty"`
+	ToYear      string           `json:"to_year,omitempty"`
+	GraduatedOn models.Date      `json:"graduation_date"`
+	DryRun      bool             `json:"dry_run"`
+	Promoted    int              `json:"promoted"`
+	Graduated   int              `json:"graduated"`
+	Classes     []ClassPromotion `json:"classes"`
+}
+
+// classGradePattern matches a leading arabic grade ("7A", "10 IPA") or a
+// roman grade followed by a separator ("VII B", "X-IPA-1")
+var classGradePattern = regexp.MustCompile(`^(?:(\d{1,2})(\D.*)?|([IVX]{1,4})([\s\-./].*)?)$`)
+
+var romanGrades = []string{"", "I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// NextClassLevel returns the class a student moves to, keeping the class's
+// numbering style: "7A" -> "8A", "VII B" -> "VIII B", "X IPA 1" -> "XI IPA 1".
+// graduates is true for classes of finalGrade; ok is false if no grade is found.
+func NextClassLevel(class string, finalGrade int) (next string, graduates bool, ok bool) {
+	m := classGradePattern.FindStringSubmatch(strings.TrimSpace(class))
+	if m == nil {
+		return "", false, false
+	}
+
+	if m[1] != "" {
+		grade, _ := strconv.Atoi(m[1])
+		if grade == 0 {
+			return "", false, false
+		}
+		if grade >= finalGrade {
+			return "", true, true
+		}
+		return strconv.Itoa(grade+1) + m[2], false, true
+	}
+
+	grade := romanGrade(m[3])
+	if grade == 0 {
+		return "", false, false
+	}
+	if grade >= finalGrade {
+		return "", true, true
+	}
+	if grade+1 >= len(romanGrades) {
+		return "", false, false
+	}
+	return romanGrades[grade+1] + m[4], false, true
+}
+
+// romanGrade returns the grade of a roman numeral, or 0 if it is not one
+func romanGrade(s string) int {
+	for i, r := range romanGrades {
+		if i > 0 && r == s {
+			return i
+		}
+	}
+	return 0
+}
+
+// PromoteYear moves every active student of FromYear to the next class and
+// ToYear, and graduates the final grade. Promoted students leave FromYear, so
+// running it again finds nobody to promote. The whole promotion runs in one
+// transaction and fails without changes if any class cannot be mapped.
+func (s *UserService) PromoteYear(opts PromotionOptions) (*PromotionReport, error) {
+	if err := validatePromotionYears(opts); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("role = ? AND is_active = ? AND class_level IS NOT NULL AND class_level <> ''", "student", true).
+		Where("academic_year = ?", opts.FromYear)
+
+	var students []models.User
+	if err := query.Order("id").Find(&students).Error; err != nil {
+		return nil, err
+	}
+
+	// Work out every class mapping before touching anything
+	byClass := make(map[string][]models.User)
+	for _, student := range students {
+		byClass[*student.ClassLevel] = append(byClass[*student.ClassLevel], student)
+	}
+
+	report := &PromotionReport{FromYear: opts.FromYear, ToYear: opts.ToYear, GraduatedOn: opts.GraduatedOn, DryRun: opts.DryRun, Classes: []ClassPromotion{}}
+	var unknown []string
+	for class, members := range byClass {
+		promotion := ClassPromotion{From: class, Students: len(members)}
+		if target, ok := opts.Classes[class]; ok {
+			promotion.To = target
+			promotion.Graduates = target == ""
+		} else if next, graduates, ok := NextClassLevel(class, opts.FinalGrade); ok {
+			promotion.To = next
+			promotion.Graduates = graduates
+		} else {
+			unknown = append(unknown, class)
+			continue
+		}
+
+		if promotion.Graduates {
+			report.Graduated += len(members)
+		} else {
+			report.Promoted += len(members)
+		}
+		report.Classes = append(report.Classes, promotion)
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, ValidationError("no promotion rule for classes: " + strings.Join(unknown, ", "))
+	}
+	sort.Slice(report.Classes, func(i, j int) bool { return report.Classes[i].From < report.Classes[j].From })
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, promotion := range report.Classes {
+			for _, student := range byClass[promotion.From] {
+				if err := promoteStudent(tx, &student, promotion, opts); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// validatePromotionYears requires distinct from and to academic years
+func validatePromotionYears(opts PromotionOptions) error {
+	var fields []FieldError
+	if opts.FromYear == "" {
+		fields = append(fields, FieldError{Field: "from_year", Code: "required", Message: "from_year is required"})
+	}
+	if opts.ToYear == "" {
+		fields = append(fields, FieldError{Field: "to_year", Code: "required", Message: "to_year is required"})
+	}
+	if len(fields) == 0 && opts.FromYear == opts.ToYear {
+		fields = append(fields, FieldError{Field: "to_year", Code: "invalid", Message: "to_year must differ from from_year"})
+	}
+	if len(fields) > 0 {
+		return ValidationError("promotion needs distinct from and to academic years", fields...)
+	}
+	return nil
+}
+
+// promoteStudent applies one class promotion to a student through applyUpdateTx
+func promoteStudent(tx *gorm.DB, student *models.User, promotion ClassPromotion, opts PromotionOptions) error {
+	updateData := map[string]interface{}{}
+	if promotion.Graduates {
+		updateData["is_active"] = false
+		updateData["status"] = "graduated"
+		updateData["graduation_date"] = opts.GraduatedOn
+	} else {
+		updateData["class_level"] = promotion.To
+		updateData["academic_year"] = opts.ToYear
+	}
+
+	var updated models.User
+	err := applyUpdateTx(tx, &updated, student.ID, student.Version, updateData)
+	var conflict *VersionConflictError
+	if errors.As(err, &conflict) {
+		return StateError("concurrent_update", fmt.Sprintf("user %d was modified during promotion", student.ID))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to promote user %d: %w", student.ID, err)
+	}
+	return nil
+}
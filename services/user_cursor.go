@@ -0,0 +1,240 @@
+// user-service/services/user_cursor.go - Keyset (cursor) pagination for user listings
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// cursorColumns are the sort keys usable with cursors.
+// Keyset pagination needs NOT NULL columns, so nullable keys are excluded.
+var cursorColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"username":   true,
+	"email":      true,
+	"role":       true,
+}
+
+// Cursor is the decoded position of a keyset page boundary
+type Cursor struct {
+	Sort     string            `json:"s"`
+	Values   map[string]string `json:"v"`
+	ID       uint              `json:"id"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// UserPage is a page of users returned by cursor pagination
+type UserPage struct {
+	Users      []models.User
+	NextCursor string
+	PrevCursor string
+}
+
+// EncodeCursor returns the opaque string form of a cursor
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses an opaque cursor string
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, FieldValidationError("cursor", "invalid", "invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, FieldValidationError("cursor", "invalid", "invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// SortSpec returns the canonical string form of sort fields
+func SortSpec(sort []SortField) string {
+	parts := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Desc {
+			parts = append(parts, "-"+field.Key)
+		} else {
+			parts = append(parts, field.Key)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ValidateCursorSort checks that every sort key can be used with cursors
+func ValidateCursorSort(sort []SortField) error {
+	for _, field := range sort {
+		if !cursorColumns[field.Key] {
+			return FieldValidationError("sort", "invalid", fmt.Sprintf("sort key %q cannot be used with cursor pagination", field.Key))
+		}
+	}
+	return nil
+}
+
+// GetUsersByCursor retrieves a page of users after (or before) the given cursor.
+// A nil cursor returns the first page. The caller checks, before running any
+// other query for the listing, that sort passes ValidateCursorSort and that
+// the cursor was issued for the same sort.
+func (s *UserService) GetUsersByCursor(filter UserFilter, sort []SortField, cursor *Cursor, limit int) (*UserPage, error) {
+	spec := SortSpec(sort)
+	keys := keysetFields(sort)
+	backward := cursor != nil && cursor.Backward
+
+	query := applyUserFilter(s.db.Model(&models.User{}), filter)
+
+	if cursor != nil {
+		condition, args, err := keysetCondition(keys, cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(condition, args...)
+	}
+
+	// Walking backwards reverses the order, the page is flipped back below
+	order := keys
+	if backward {
+		order = make([]SortField, len(keys))
+		for i, field := range keys {
+			order[i] = SortField{Key: field.Key, Desc: !field.Desc}
+		}
+	}
+
+	var users []models.User
+	result := applyUserSort(query, order).Limit(limit + 1).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+
+	if backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+	}
+
+	page := &UserPage{Users: users}
+	if len(users) == 0 {
+		return page, nil
+	}
+
+	first, last := &users[0], &users[len(users)-1]
+	if backward {
+		// Came from a later page, so there is always a next page
+		page.NextCursor = EncodeCursor(cursorFor(last, keys, spec, false))
+		if hasMore {
+			page.PrevCursor = EncodeCursor(cursorFor(first, keys, spec, true))
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = EncodeCursor(cursorFor(last, keys, spec, false))
+		}
+		if cursor != nil {
+			page.PrevCursor = EncodeCursor(cursorFor(first, keys, spec, true))
+		}
+	}
+
+	return page, nil
+}
+
+// keysetFields returns the sort fields with id appended as the tie-breaker
+func keysetFields(sort []SortField) []SortField {
+	for _, field := range sort {
+		if field.Key == "id" {
+			return sort
+		}
+	}
+	return append(append([]SortField{}, sort...), SortField{Key: "id"})
+}
+
+// keysetCondition builds "(k1 > v1) OR (k1 = v1 AND k2 > v2) ..." for the cursor
+func keysetCondition(keys []SortField, cursor *Cursor) (string, []interface{}, error) {
+	values := make([]interface{}, len(keys))
+	for i, field := range keys {
+		value, err := cursorValue(field.Key, cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		values[i] = value
+	}
+
+	var clauses []string
+	var args []interface{}
+
+	for i, field := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, sortableColumns[keys[j].Key]+" = ?")
+			args = append(args, values[j])
+		}
+
+		op := ">"
+		if field.Desc != cursor.Backward {
+			op = "<"
+		}
+		parts = append(parts, sortableColumns[field.Key]+" "+op+" ?")
+		args = append(args, values[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return strings.Join(clauses, " OR "), args, nil
+}
+
+// cursorValue returns the typed value of a sort key stored in the cursor
+func cursorValue(key string, cursor *Cursor) (interface{}, error) {
+	if key == "id" {
+		return cursor.ID, nil
+	}
+
+	raw, ok := cursor.Values[key]
+	if !ok {
+		return nil, FieldValidationError("cursor", "invalid", "invalid cursor")
+	}
+
+	switch key {
+	case "created_at", "updated_at":
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, FieldValidationError("cursor", "invalid", "invalid cursor")
+		}
+		return t, nil
+	default:
+		return raw, nil
+	}
+}
+
+// cursorFor builds the cursor pointing at the given user
+func cursorFor(user *models.User, keys []SortField, spec string, backward bool) Cursor {
+	cursor := Cursor{Sort: spec, Values: make(map[string]string), ID: user.ID, Backward: backward}
+
+	for _, field := range keys {
+		switch field.Key {
+		case "created_at":
+			cursor.Values[field.Key] = user.CreatedAt.Format(time.RFC3339Nano)
+		case "updated_at":
+			cursor.Values[field.Key] = user.UpdatedAt.Format(time.RFC3339Nano)
+		case "username":
+			cursor.Values[field.Key] = user.Username
+		case "email":
+			cursor.Values[field.Key] = user.Email
+		case "role":
+			cursor.Values[field.Key] = user.Role
+		}
+	}
+
+	return cursor
+}
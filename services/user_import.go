@@ -0,0 +1,170 @@
+// user-service/services/user_import.go - Validated bulk import of users
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// ImportRowError lists every problem found in one row of an import
+type ImportRowError struct {
+	Row      int          `json:"row"`
+	Username string       `json:"username,omitempty"`
+	Errors   []FieldError `json:"errors"`
+}
+
+// ImportReport summarises a bulk import
+type ImportReport struct {
+	Total    int              `json:"total"`
+	Created  int              `json:"created"`
+	Failed   int              `json:"failed"`
+	DryRun   bool             `json:"dry_run"`
+	Failures []ImportRowError `json:"failures,omitempty"`
+}
+
+// ImportUsers is the bulk create path, used by the import command. It
+// validates every row, reporting every problem, and when all rows are valid
+// creates the users in a single transaction. Rows are numbered from 1.
+//
+// validate checks the request struct tags (the API's validator); it may be nil.
+// With dryRun the rows are only validated.
+func (s *UserService) ImportUsers(rows []models.CreateUserRequest, validate func(interface{}) error, dryRun bool) (*ImportReport, error) {
+	report := &ImportReport{Total: len(rows), DryRun: dryRun}
+	users := make([]models.User, 0, len(rows))
+	seen := make(map[string]int)
+
+	for i := range rows {
+		req := &rows[i]
+		var problems []error
+
+		if validate != nil {
+			if err := validate(req); err != nil {
+				problems = append(problems, err)
+			}
+		}
+
+		if err := utils.ValidatePasswordForUser(req.Password, passwordContext(req)); err != nil {
+			problems = append(problems, err)
+		}
+
+		user := newUserFromRequest(req, "")
+		if err := s.ValidateRoleRequiredFields(&user); err != nil {
+			problems = append(problems, err)
+		}
+
+		for _, unique := range importUniqueValues(&user) {
+			key := unique.field + ":" + unique.value
+			if first, ok := seen[key]; ok {
+				duplicateErr := FieldValidationError(unique.field, "duplicate_row", fmt.Sprintf("%s duplicates row %d", unique.field, first))
+				duplicateErr.Params = map[string]string{"row": strconv.Itoa(first)}
+				problems = append(problems, duplicateErr)
+			} else {
+				seen[key] = i + 1
+			}
+		}
+
+		field, err := s.findUniqueConflict(&user)
+		if err != nil {
+			return nil, err
+		}
+		if field != "" {
+			problems = append(problems, ConflictError(field, field+" is already used by another user"))
+		}
+
+		if len(problems) > 0 {
+			var fieldErrors []FieldError
+			for _, problem := range problems {
+				fieldErrors = append(fieldErrors, importFieldErrors(problem)...)
+			}
+			report.Failures = append(report.Failures, ImportRowError{Row: i + 1, Username: req.Username, Errors: fieldErrors})
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	report.Failed = len(report.Failures)
+	if report.Failed > 0 || dryRun || len(users) == 0 {
+		return report, nil
+	}
+
+	// Hash only once the whole file is known to be valid; with no failures
+	// users and rows line up one to one
+	for i := range users {
+		hashedPassword, err := utils.HashPassword(rows[i].Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %v", err)
+		}
+		users[i].PasswordHash = hashedPassword
+		users[i].DefaultPassword = boolPtr(isDefaultPassword(rows[i].Password))
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&users, 100).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import failed: %v", err)
+	}
+
+	report.Created = len(users)
+	return report, nil
+}
+
+// importFieldErrors describes one problem of an import row in English
+func importFieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]FieldError, len(validationErrors))
+		for i, fieldErr := range validationErrors {
+			fields[i] = FieldError{Field: fieldErr.Field(), Code: fieldErr.Tag(), Message: fieldErr.Error()}
+		}
+		return fields
+	}
+
+	var policyErr *utils.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		fields := make([]FieldError, len(policyErr.Violations))
+		for i, violation := range policyErr.Violations {
+			fields[i] = FieldError{Field: "password", Code: violation.Code, Message: violation.String()}
+		}
+		return fields
+	}
+
+	if domainErr, ok := AsError(err); ok {
+		if len(domainErr.Fields) > 0 {
+			return domainErr.Fields
+		}
+		return []FieldError{{Field: domainErr.Field, Code: domainErr.Code, Message: domainErr.Message}}
+	}
+
+	return []FieldError{{Code: "invalid", Message: err.Error()}}
+}
+
+// importUniqueValues returns the unique fields of a user that are set
+func importUniqueValues(user *models.User) []struct{ field, value string } {
+	values := []struct{ field, value string }{
+		{"username", user.Username},
+		{"email", user.Email},
+	}
+	optional := []struct {
+		field string
+		value *string
+	}{
+		{"employee_id", user.EmployeeID},
+		{"student_id", user.StudentID},
+		{"nisn", user.NISN},
+	}
+	for _, o := range optional {
+		if o.value != nil && *o.value != "" {
+			values = append(values, struct{ field, value string }{o.field, *o.value})
+		}
+	}
+	return values
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+func TestImportFieldErrors(t *testing.T) {
+	policyErr := &utils.PasswordPolicyError{Violations: []utils.PasswordViolation{{Code: "password_breached"}, {Code: "password_too_short"}}}
+	fields := importFieldErrors(policyErr)
+	if len(fields) != 2 || fields[0].Field != "password" || fields[1].Code != "password_too_short" {
+		t.Errorf("password violations = %+v, want one password field error each", fields)
+	}
+
+	fields = importFieldErrors(ConflictError("email", "email is already used by another user"))
+	if len(fields) != 1 || fields[0].Field != "email" || fields[0].Code != "conflict" {
+		t.Errorf("conflict = %+v, want an email conflict", fields)
+	}
+
+	fields = importFieldErrors(errors.New("broken row"))
+	if len(fields) != 1 || fields[0].Message != "broken row" {
+		t.Errorf("other error = %+v, want its message", fields)
+	}
+}
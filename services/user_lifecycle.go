@@ -0,0 +1,202 @@
+// user-service/services/user_lifecycle.go - Trash, restore and purge of users
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// GetTrashedUsers retrieves soft-deleted users, most recently deleted first
+func (s *UserService) GetTrashedUsers(page, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := s.db.Unscoped().Model(&models.User{}).Where("deleted_at IS NOT NULL")
+
+	countResult := query.Count(&total)
+	if countResult.Error != nil {
+		return nil, 0, countResult.Error
+	}
+
+	offset := (page - 1) * limit
+	result := query.Order("deleted_at DESC, id ASC").Offset(offset).Limit(limit).Find(&users)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return users, total, nil
+}
+
+// GetTrashedUser retrieves a single soft-deleted user
+func (s *UserService) GetTrashedUser(userID uint) (*models.User, error) {
+	var user models.User
+	result := s.db.Unscoped().Where("deleted_at IS NOT NULL").First(&user, userID)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, NotFoundError("trashed_user", "user not found in trash")
+		}
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// RestoreUser moves a user out of the trash.
+// It fails if a live user has since taken the same username, email or IDs.
+func (s *UserService) RestoreUser(userID uint) (*models.User, error) {
+	user, err := s.GetTrashedUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if field, err := s.findUniqueConflict(user); err != nil {
+		return nil, err
+	} else if field != "" {
+		return nil, ConflictError(field, field+" is already used by another user")
+	}
+
+	// A user created since the check above still trips the unique indexes
+	result := s.db.Unscoped().Model(&models.User{}).Where("id = ?", userID).Update("deleted_at", nil)
+	if result.Error != nil {
+		return nil, translateDBError(result.Error, "failed to restore user")
+	}
+
+	return s.GetUserByID(userID)
+}
+
+// PurgeUser permanently deletes a trashed user once the retention period has passed
+func (s *UserService) PurgeUser(userID uint, retention time.Duration) error {
+	user, err := s.GetTrashedUser(userID)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-retention)
+	if user.DeletedAt.Time.After(cutoff) {
+		purgeAfter := user.DeletedAt.Time.Add(retention)
+		err := StateError("retention_period_active", "user cannot be purged before "+purgeAfter.Format(time.RFC3339))
+		err.Params = map[string]string{"purge_after": purgeAfter.Format(time.RFC3339)}
+		return err
+	}
+
+	// The conditions are checked again so a concurrent restore is never purged
+	purged, err := s.purgeUsers(cutoff, userID)
+	if err != nil {
+		return fmt.Errorf("failed to purge user: %v", err)
+	}
+	if purged == 0 {
+		return NotFoundError("trashed_user", "user not found in trash")
+	}
+
+	return nil
+}
+
+// PurgeExpiredUsers permanently deletes every trashed user past the retention period
+func (s *UserService) PurgeExpiredUsers(retention time.Duration) (int64, error) {
+	return s.purgeUsers(time.Now().Add(-retention))
+}
+
+// purgeUsers permanently deletes users trashed at or before cutoff, limited
+// to userIDs when given, together with their password history and revisions,
+// which have no foreign key to cascade from
+func (s *UserService) purgeUsers(cutoff time.Time, userIDs ...uint) (int64, error) {
+	var purged []models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("deleted_at IS NOT NULL AND deleted_at <= ?", cutoff)
+		if len(userIDs) > 0 {
+			query = query.Where("id IN ?", userIDs)
+		}
+
+		result := query.Delete(&purged)
+		if result.Error != nil || len(purged) == 0 {
+			return result.Error
+		}
+
+		ids := make([]uint, len(purged))
+		for i, user := range purged {
+			ids[i] = user.ID
+		}
+		if err := tx.Where("user_id IN ?", ids).Delete(&models.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id IN ?", ids).Delete(&models.UserRevision{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(purged)), nil
+}
+
+// StartTrashSweeper purges expired trash every interval until stop is called
+func (s *UserService) StartTrashSweeper(interval, retention time.Duration) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				purged, err := s.PurgeExpiredUsers(retention)
+				if err != nil {
+					slog.Warn("Failed to purge expired users", "error", err)
+				} else if purged > 0 {
+					slog.Info("Purged users past the trash retention period", "count", purged)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// findUniqueConflict returns the first unique field of user that a live user already uses
+func (s *UserService) findUniqueConflict(user *models.User) (string, error) {
+	checks := []struct {
+		field string
+		value *string
+	}{
+		{"username", &user.Username},
+		{"email", &user.Email},
+		{"employee_id", user.EmployeeID},
+		{"student_id", user.StudentID},
+		{"nisn", user.NISN},
+	}
+
+	for _, check := range checks {
+		if check.value == nil || *check.value == "" {
+			continue
+		}
+
+		var count int64
+		result := s.db.Model(&models.User{}).
+			Where(check.field+" = ? AND id <> ?", *check.value, user.ID).
+			Count(&count)
+		if result.Error != nil {
+			return "", result.Error
+		}
+		if count > 0 {
+			return check.field, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,215 @@
+// user-service/services/user_list.go - Filtering and sorting for user listings
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/models"
+	"gitlab.com/nodiviti/user-service/utils"
+)
+
+// UserFilter holds the allowlisted filters for user listings.
+// Every filter maps to a fixed column and is passed as a bound parameter.
+type UserFilter struct {
+	Role            string              `json:"role,omitempty"`
+	ClassLevel      string              `json:"class_level,omitempty"`
+	AcademicYear    string              `json:"academic_year,omitempty"`
+	Status          string              `json:"status,omitempty"`
+	Gender          string              `json:"gender,omitempty"`
+	IsActive        *bool               `json:"is_active,omitempty"`
+	IncludeInactive bool                `json:"include_inactive,omitempty"`
+	HasPhoto        *bool               `json:"has_photo,omitempty"`
+	CreatedFrom     *time.Time          `json:"created_from,omitempty"`
+	CreatedTo       *time.Time          `json:"created_to,omitempty"`
+	EnrollmentYear  *utils.AcademicYear `json:"enrollment_year,omitempty"`
+}
+
+// SortField is a single validated sort key
+type SortField struct {
+	Key  string
+	Desc bool
+}
+
+// sortableColumns maps public sort keys to columns
+var sortableColumns = map[string]string{
+	"id":            "id",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+	"username":      "username",
+	"email":         "email",
+	"full_name":     "full_name",
+	"role":          "role",
+	"status":        "status",
+	"class_level":   "class_level",
+	"academic_year": "academic_year",
+}
+
+// DefaultUserSort is the listing order used when no sort is given
+var DefaultUserSort = []SortField{{Key: "created_at", Desc: true}}
+
+// ParseSort parses a sort spec such as "-created_at,full_name".
+// A leading "-" sorts descending; keys must be in the allowlist.
+func ParseSort(spec string) ([]SortField, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultUserSort, nil
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		key := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+
+		if _, ok := sortableColumns[key]; !ok {
+			return nil, FieldValidationError("sort", "invalid", fmt.Sprintf("invalid sort key: %q", key))
+		}
+		if seen[key] {
+			return nil, FieldValidationError("sort", "duplicate", fmt.Sprintf("duplicate sort key: %q", key))
+		}
+		seen[key] = true
+
+		fields = append(fields, SortField{Key: key, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// applyUserFilter adds filter conditions to a users query
+func applyUserFilter(query *gorm.DB, filter UserFilter) *gorm.DB {
+	switch {
+	case filter.IsActive != nil:
+		query = query.Where("is_active = ?", *filter.IsActive)
+	case !filter.IncludeInactive:
+		query = query.Where("is_active = ?", true)
+	}
+
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.ClassLevel != "" {
+		query = query.Where("class_level = ?", filter.ClassLevel)
+	}
+	if filter.AcademicYear != "" {
+		query = query.Where("academic_year = ?", filter.AcademicYear)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Gender != "" {
+		query = query.Where("gender = ?", filter.Gender)
+	}
+	if filter.HasPhoto != nil {
+		if *filter.HasPhoto {
+			query = query.Where("profile_photo IS NOT NULL AND profile_photo <> ''")
+		} else {
+			query = query.Where("profile_photo IS NULL OR profile_photo = ''")
+		}
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+
+	// Enrollment year filter (Gregorian or Hijri boundaries)
+	if filter.EnrollmentYear != nil {
+		query = query.Where("enrollment_date >= ? AND enrollment_date < ?", filter.EnrollmentYear.Start, filter.EnrollmentYear.End)
+	}
+
+	return query
+}
+
+// applyUserSort adds ORDER BY clauses, always ending with id for a stable order
+func applyUserSort(query *gorm.DB, sort []SortField) *gorm.DB {
+	hasID := false
+	for _, field := range sort {
+		column := sortableColumns[field.Key]
+		if column == "" {
+			continue
+		}
+
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		query = query.Order(column + " " + direction + " NULLS LAST")
+
+		if field.Key == "id" {
+			hasID = true
+		}
+	}
+
+	if !hasID {
+		query = query.Order("id ASC")
+	}
+
+	return query
+}
+
+// CountMode selects how listings report their total
+type CountMode string
+
+const (
+	CountExact     CountMode = "exact"     // SELECT COUNT(*)
+	CountEstimated CountMode = "estimated" // planner row estimate, cheap on large tables
+	CountNone      CountMode = "none"      // no total
+)
+
+// ParseCountMode validates a count mode, using def when empty
+func ParseCountMode(s string, def CountMode) (CountMode, error) {
+	switch CountMode(s) {
+	case "":
+		return def, nil
+	case CountExact, CountEstimated, CountNone:
+		return CountMode(s), nil
+	}
+	return "", FieldValidationError("count", "invalid", "invalid count: must be exact, estimated or none")
+}
+
+// CountUsers counts users matching the filter. It returns nil for CountNone.
+func (s *UserService) CountUsers(filter UserFilter, mode CountMode) (*int64, error) {
+	var total int64
+
+	switch mode {
+	case CountNone:
+		return nil, nil
+
+	case CountEstimated:
+		// Ask the planner instead of scanning: EXPLAIN the filtered query
+		stmt := applyUserFilter(s.db.Model(&models.User{}), filter).
+			Session(&gorm.Session{DryRun: true}).
+			Find(&[]models.User{}).Statement
+
+		// Through GORM, so the request context, tracing and logging apply
+		var plan string
+		if err := s.db.Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).Scan(&plan).Error; err != nil {
+			return nil, err
+		}
+
+		var explain []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal([]byte(plan), &explain); err != nil || len(explain) == 0 {
+			return nil, fmt.Errorf("failed to read row estimate")
+		}
+		total = int64(explain[0].Plan.Rows)
+
+	default:
+		result := applyUserFilter(s.db.Model(&models.User{}), filter).Count(&total)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+	}
+
+	return &total, nil
+}
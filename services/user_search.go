@@ -0,0 +1,161 @@
+// user-service/services/user_search.go - Ranked fuzzy user search
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// searchMatchThreshold is the minimum per-field score for a field to count as matched.
+// It mirrors pg_trgm's default word_similarity_threshold used by the <% operator.
+const searchMatchThreshold = 0.6
+
+// SearchOptions holds search query and filters
+type SearchOptions struct {
+	Query      string
+	Role       string
+	ClassLevel string
+	Status     string
+	Limit      int
+}
+
+// SearchResult is a ranked search hit
+type SearchResult struct {
+	User          models.User
+	Score         float64
+	MatchedFields []string
+}
+
+// searchRow is the raw row returned by the search query
+type searchRow struct {
+	models.User
+
+	FullNameScore   float64
+	UsernameScore   float64
+	EmailScore      float64
+	ParentNameScore float64
+	FullNameText    bool
+	UsernameText    bool
+	EmailText       bool
+	ParentNameText  bool
+	TextRank        float64
+	IDMatch         string
+	Score           float64
+}
+
+// SearchUsers searches users by name, username, email, identifiers and parent name.
+// Names are compared with trigram word similarity after transliteration
+// normalization (see user_search_norm), so "Muhamad" finds "Muhammad".
+func (s *UserService) SearchUsers(opts SearchOptions) ([]SearchResult, error) {
+	var rows []searchRow
+	if err := s.searchQuery(opts).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, SearchResult{
+			User:          row.User,
+			Score:         row.Score,
+			MatchedFields: row.matchedFields(),
+		})
+	}
+
+	return results, nil
+}
+
+// searchQuery builds the ranked search query of SearchUsers
+func (s *UserService) searchQuery(opts SearchOptions) *gorm.DB {
+	q := strings.TrimSpace(opts.Query)
+	prefix := escapeLike(q) + "%"
+
+	// search_vector spans every field, so its terms may be spread across them:
+	// a field counts as a full-text hit when it holds any of the query terms
+	anyTerm := `replace(plainto_tsquery('simple', @q)::text, '&', '|')::tsquery`
+
+	scores := `word_similarity(user_search_norm(@q), user_search_norm(users.full_name)) AS full_name_score,
+		word_similarity(user_search_norm(@q), user_search_norm(users.username)) AS username_score,
+		word_similarity(user_search_norm(@q), user_search_norm(users.email)) AS email_score,
+		word_similarity(user_search_norm(@q), user_search_norm(users.parent_name)) AS parent_name_score,
+		to_tsvector('simple', coalesce(users.full_name, '')) @@ ` + anyTerm + ` AS full_name_text,
+		to_tsvector('simple', coalesce(users.username, '')) @@ ` + anyTerm + ` AS username_text,
+		to_tsvector('simple', coalesce(users.email, '')) @@ ` + anyTerm + ` AS email_text,
+		to_tsvector('simple', coalesce(users.parent_name, '')) @@ ` + anyTerm + ` AS parent_name_text,
+		ts_rank(users.search_vector, plainto_tsquery('simple', @q)) AS text_rank,
+		CASE
+			WHEN users.student_id ILIKE @prefix THEN 'student_id'
+			WHEN users.employee_id ILIKE @prefix THEN 'employee_id'
+			WHEN users.nisn LIKE @prefix THEN 'nisn'
+			ELSE ''
+		END AS id_match`
+
+	// Parenthesized so the filters and soft delete apply to every alternative
+	match := `(user_search_norm(@q) <% user_search_norm(users.full_name)
+		OR user_search_norm(@q) <% user_search_norm(users.username)
+		OR user_search_norm(@q) <% user_search_norm(users.email)
+		OR user_search_norm(@q) <% user_search_norm(users.parent_name)
+		OR users.search_vector @@ plainto_tsquery('simple', @q)
+		OR users.student_id ILIKE @prefix
+		OR users.employee_id ILIKE @prefix
+		OR users.nisn LIKE @prefix)`
+
+	args := map[string]interface{}{"q": q, "prefix": prefix}
+
+	db := s.db.Model(&models.User{}).Where("users.is_active = ?", true)
+
+	if opts.Role != "" {
+		db = db.Where("users.role = ?", opts.Role)
+	}
+	if opts.ClassLevel != "" {
+		db = db.Where("users.class_level = ?", opts.ClassLevel)
+	}
+	if opts.Status != "" {
+		db = db.Where("users.status = ?", opts.Status)
+	}
+
+	return s.db.Table("(?) AS ranked", db.
+		Select("users.*, "+scores, args).
+		Where(match, args)).
+		Select(`ranked.*, GREATEST(full_name_score, username_score, email_score, parent_name_score)
+			+ text_rank
+			+ CASE WHEN id_match <> '' THEN 1 ELSE 0 END AS score`).
+		Order("score DESC, ranked.id ASC").
+		Limit(opts.Limit)
+}
+
+// matchedFields lists the fields whose score passed the match threshold or
+// that matched the full-text query on their own
+func (r *searchRow) matchedFields() []string {
+	fields := []string{}
+
+	scored := []struct {
+		field string
+		score float64
+		text  bool
+	}{
+		{"full_name", r.FullNameScore, r.FullNameText},
+		{"username", r.UsernameScore, r.UsernameText},
+		{"email", r.EmailScore, r.EmailText},
+		{"parent_name", r.ParentNameScore, r.ParentNameText},
+	}
+	for _, f := range scored {
+		if f.score >= searchMatchThreshold || f.text {
+			fields = append(fields, f.field)
+		}
+	}
+	if r.IDMatch != "" {
+		fields = append(fields, r.IDMatch)
+	}
+
+	sort.Strings(fields)
+	return fields
+}
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
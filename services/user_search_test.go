@@ -0,0 +1,205 @@
+package services
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/database"
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// dryRunDB renders PostgreSQL statements without connecting to a database
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=dryrun"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestSearchQueryFiltersApplyToEveryMatch(t *testing.T) {
+	service := &UserService{db: dryRunDB(t)}
+	opts := SearchOptions{Query: "muh", Role: "student", ClassLevel: "7A", Status: "active", Limit: 20}
+
+	var rows []searchRow
+	stmt := service.searchQuery(opts).Find(&rows)
+	if stmt.Error != nil {
+		t.Fatal(stmt.Error)
+	}
+	sql := stmt.Statement.SQL.String()
+	sql = regexp.MustCompile(`\s+`).ReplaceAllString(sql, " ")
+
+	// Every alternative of the match sits in one group between the filters
+	// and the soft delete condition
+	match := regexp.MustCompile(`users\.status = \$\d+ AND \((.*)\) AND "users"\."deleted_at" IS NULL`).FindStringSubmatch(sql)
+	if match == nil {
+		t.Fatalf("filters and soft delete do not enclose the match:\n%s", sql)
+	}
+	for _, filter := range []string{"users.is_active = $", "users.role = $", "users.class_level = $"} {
+		if !strings.Contains(sql, filter) {
+			t.Errorf("query lacks %q:\n%s", filter, sql)
+		}
+	}
+
+	// The group is balanced, so its parentheses enclose every OR
+	depth := 0
+	for i, r := range match[1] {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 {
+			t.Fatalf("match group closes early at %d:\n%s", i, sql)
+		}
+	}
+	if !strings.Contains(match[1], " OR ") {
+		t.Fatalf("match group holds no alternatives:\n%s", sql)
+	}
+}
+
+func TestMatchedFieldsReportsFullTextHits(t *testing.T) {
+	row := searchRow{FullNameScore: 0.4, UsernameScore: 0.7, ParentNameText: true, IDMatch: "nisn"}
+	if got, want := row.matchedFields(), []string{"nisn", "parent_name", "username"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matchedFields() = %v, want %v", got, want)
+	}
+
+	if got := (&searchRow{}).matchedFields(); got == nil || len(got) != 0 {
+		t.Errorf("matchedFields() of a row without hits = %#v, want an empty list", got)
+	}
+}
+
+// searchDB connects to the PostgreSQL database named by USER_SERVICE_TEST_DSN
+// and migrates it. The users table is emptied, so point it at a throwaway
+// database; the tests are skipped when it is unset.
+func searchDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("USER_SERVICE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("USER_SERVICE_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.DB = db
+	if err := database.AutoMigrate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec("TRUNCATE users RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedSearchUsers stores students with the given username, full name and parent name
+func seedSearchUsers(t *testing.T, db *gorm.DB, rows ...[3]string) {
+	t.Helper()
+	for _, row := range rows {
+		user := models.User{
+			Username:     row[0],
+			Email:        row[0] + "@school.test",
+			PasswordHash: "-",
+			Role:         "student",
+			IsActive:     true,
+			FullName:     &row[1],
+		}
+		if row[2] != "" {
+			user.ParentName = &row[2]
+		}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func searchUsernames(results []SearchResult) []string {
+	usernames := make([]string, 0, len(results))
+	for _, result := range results {
+		usernames = append(usernames, result.User.Username)
+	}
+	return usernames
+}
+
+func TestSearchUsersToleratesTypos(t *testing.T) {
+	db := searchDB(t)
+	seedSearchUsers(t, db,
+		[3]string{"mrizki", "Muhammad Rizki", ""},
+		[3]string{"mfauzi", "Mohamad Fauzi", ""},
+		[3]string{"saminah", "Siti Aminah", "Muhammad Yusuf"},
+		[3]string{"bsantoso", "Budi Santoso", ""},
+	)
+	service := &UserService{db: db}
+
+	results, err := service.SearchUsers(SearchOptions{Query: "Muhamad", Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := searchUsernames(results)
+	if want := []string{"mfauzi", "mrizki", "saminah"}; !reflect.DeepEqual(sortedCopy(got), want) {
+		t.Fatalf("search for %q = %v, want %v", "Muhamad", got, want)
+	}
+	for _, result := range results {
+		if result.User.Username == "saminah" && !reflect.DeepEqual(result.MatchedFields, []string{"parent_name"}) {
+			t.Errorf("saminah matched %v, want [parent_name]", result.MatchedFields)
+		}
+	}
+}
+
+func TestSearchUsersRanksCloserMatchesFirst(t *testing.T) {
+	db := searchDB(t)
+	seedSearchUsers(t, db,
+		[3]string{"rramadhan", "Rizky Ramadhan", ""},
+		[3]string{"rpratama", "Rizki Pratama", ""},
+	)
+	service := &UserService{db: db}
+
+	// The exact word also hits full-text search, the misspelt one only trigrams
+	results, err := service.SearchUsers(SearchOptions{Query: "Rizki", Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := searchUsernames(results), []string{"rpratama", "rramadhan"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("search for %q ranked %v, want %v", "Rizki", got, want)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("scores %v and %v are not descending", results[0].Score, results[1].Score)
+	}
+}
+
+func TestSearchUsersReportsFullTextMatches(t *testing.T) {
+	db := searchDB(t)
+	seedSearchUsers(t, db, [3]string{"rr01", "Rizki Ramadhan", "Wijaya Kusuma"})
+	service := &UserService{db: db}
+
+	// The terms sit in different fields, so only the combined search_vector matches
+	results, err := service.SearchUsers(SearchOptions{Query: "ramadhan wijaya", Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("search returned %v, want rr01", searchUsernames(results))
+	}
+	if got, want := results[0].MatchedFields, []string{"full_name", "parent_name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matched fields = %v, want %v", got, want)
+	}
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -2,7 +2,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	"gorm.io/gorm"
 
@@ -21,6 +23,12 @@ func NewUserService() *UserService {
 	}
 }
 
+// WithContext returns a copy of the service whose queries run with ctx, so
+// they are cancelled and traced together with the request
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	return &UserService{db: s.db.WithContext(ctx)}
+}
+
 // GetUserByID retrieves user by ID
 func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 	var user models.User
@@ -28,7 +36,22 @@ func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
+			return nil, NotFoundError("user", "user not found")
+		}
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// GetAccountState loads the columns checked on every authenticated request
+func (s *UserService) GetAccountState(id uint) (*models.User, error) {
+	var user models.User
+	result := s.db.Select("id", "must_change_password", "timezone").First(&user, id)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, NotFoundError("user", "user not found")
 		}
 		return nil, result.Error
 	}
@@ -43,7 +66,7 @@ func (s *UserService) GetUserByUsername(username string) (*models.User, error) {
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
+			return nil, NotFoundError("user", "user not found")
 		}
 		return nil, result.Error
 	}
@@ -58,7 +81,26 @@ func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
+			return nil, NotFoundError("user", "user not found")
+		}
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// FindUser retrieves a live user by ID, username or email, active or not
+func (s *UserService) FindUser(ref string) (*models.User, error) {
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		return s.GetUserByID(uint(id))
+	}
+
+	var user models.User
+	result := s.db.Where("username = ? OR email = ?", ref, ref).First(&user)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, NotFoundError("user", "user not found")
 		}
 		return nil, result.Error
 	}
@@ -69,13 +111,22 @@ func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
 // CreateUser creates a new user (removed - will be handled by auth-service register)
 // This method is kept for admin-only user creation
 func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.User, error) {
-	// Check if user already exists
-	exists, err := s.CheckUserExists(req.Username, req.Email)
-	if err != nil {
+	user := newUserFromRequest(req, "")
+
+	// Check if any unique field is already taken
+	if field, err := s.findUniqueConflict(&user); err != nil {
 		return nil, err
+	} else if field != "" {
+		return nil, ConflictError(field, field+" is already used by another user")
 	}
-	if exists {
-		return nil, fmt.Errorf("username or email already exists")
+
+	if err := s.ValidateRoleRequiredFields(&user); err != nil {
+		return nil, err
+	}
+
+	// Check password policy, reporting every violation
+	if err := utils.ValidatePasswordForUser(req.Password, passwordContext(req)); err != nil {
+		return nil, err
 	}
 
 	// Hash password
@@ -83,9 +134,21 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.User, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %v", err)
 	}
+	user.PasswordHash = hashedPassword
+	user.DefaultPassword = boolPtr(isDefaultPassword(req.Password))
+
+	// Create user in database with GORM
+	result := s.db.Create(&user)
+	if result.Error != nil {
+		return nil, translateDBError(result.Error, "failed to create user")
+	}
+
+	return &user, nil
+}
 
-	// Create user model
-	user := models.User{
+// newUserFromRequest builds the user model for a create request
+func newUserFromRequest(req *models.CreateUserRequest, hashedPassword string) models.User {
+	return models.User{
 		Username:     req.Username,
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
@@ -102,33 +165,27 @@ func (s *UserService) CreateUser(req *models.CreateUserRequest) (*models.User, e
 		// Role-specific fields (optional)
 		EmployeeID:     req.EmployeeID,
 		StudentID:      req.StudentID,
+		NISN:           req.NISN,
 		ClassLevel:     req.ClassLevel,
 		AcademicYear:   req.AcademicYear,
 		ParentName:     req.ParentName,
 		ParentPhone:    req.ParentPhone,
 		Specialization: req.Specialization,
 	}
-
-	// Create user in database with GORM
-	result := s.db.Create(&user)
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to create user: %v", result.Error)
-	}
-
-	return &user, nil
 }
 
-// UpdateUser updates user profile
-func (s *UserService) UpdateUser(userID uint, req *models.UpdateUserRequest) (*models.User, error) {
-	var user models.User
-	result := s.db.First(&user, userID)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
-		}
-		return nil, result.Error
+// passwordContext returns the personal information a new user's password must not contain
+func passwordContext(req *models.CreateUserRequest) utils.PasswordContext {
+	ctx := utils.PasswordContext{Username: req.Username, Email: req.Email}
+	if req.FullName != nil {
+		ctx.FullName = *req.FullName
 	}
+	return ctx
+}
 
+// UpdateUser updates user profile.
+// expectedVersion guards against lost updates; 0 skips the check.
+func (s *UserService) UpdateUser(userID uint, req *models.UpdateUserRequest, expectedVersion uint) (*models.User, error) {
 	// Update fields if provided
 	updateData := make(map[string]interface{})
 
@@ -153,6 +210,9 @@ func (s *UserService) UpdateUser(userID uint, req *models.UpdateUserRequest) (*m
 	if req.StudentID != nil {
 		updateData["student_id"] = req.StudentID
 	}
+	if req.NISN != nil {
+		updateData["nisn"] = req.NISN
+	}
 	if req.ClassLevel != nil {
 		updateData["class_level"] = req.ClassLevel
 	}
@@ -165,12 +225,27 @@ func (s *UserService) UpdateUser(userID uint, req *models.UpdateUserRequest) (*m
 	if req.ParentPhone != nil {
 		updateData["parent_phone"] = req.ParentPhone
 	}
+	if req.ParentEmail != nil {
+		updateData["parent_email"] = req.ParentEmail
+	}
+	if req.EnrollmentDate != nil {
+		updateData["enrollment_date"] = req.EnrollmentDate
+	}
 	if req.Specialization != nil {
 		updateData["specialization"] = req.Specialization
 	}
+	if req.Qualification != nil {
+		updateData["qualification"] = req.Qualification
+	}
 	if req.ExperienceYears != nil {
 		updateData["experience_years"] = req.ExperienceYears
 	}
+	if req.HireDate != nil {
+		updateData["hire_date"] = req.HireDate
+	}
+	if req.HomeroomClass != nil {
+		updateData["homeroom_class"] = req.HomeroomClass
+	}
 	if req.EmergencyContact != nil {
 		updateData["emergency_contact"] = req.EmergencyContact
 	}
@@ -180,49 +255,34 @@ func (s *UserService) UpdateUser(userID uint, req *models.UpdateUserRequest) (*m
 	if req.MedicalConditions != nil {
 		updateData["medical_conditions"] = req.MedicalConditions
 	}
+	if req.BloodType != nil {
+		updateData["blood_type"] = req.BloodType
+	}
+	if req.Timezone != nil {
+		updateData["timezone"] = req.Timezone
+	}
 	if req.Status != nil {
 		updateData["status"] = req.Status
 	}
 
-	// Update user
-	result = s.db.Model(&user).Updates(updateData)
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to update user: %v", result.Error)
-	}
-
-	// Fetch updated user
-	s.db.First(&user, userID)
-
-	return &user, nil
+	return s.applyUpdate(userID, expectedVersion, updateData)
 }
 
-// GetAllUsers retrieves users with pagination and filters
-func (s *UserService) GetAllUsers(page, limit int, role string) ([]models.User, int64, error) {
+// GetAllUsers retrieves users with offset pagination, filters and sorting
+func (s *UserService) GetAllUsers(page, limit int, filter UserFilter, sort []SortField) ([]models.User, error) {
 	var users []models.User
-	var total int64
-
-	query := s.db.Model(&models.User{}).Where("is_active = ?", true)
-
-	// Apply role filter if specified
-	if role != "" {
-		query = query.Where("role = ?", role)
-	}
 
-	// Get total count
-	countResult := query.Count(&total)
-	if countResult.Error != nil {
-		return nil, 0, countResult.Error
-	}
+	query := applyUserFilter(s.db.Model(&models.User{}), filter)
 
 	// Get paginated data
 	offset := (page - 1) * limit
-	result := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users)
+	result := applyUserSort(query, sort).Offset(offset).Limit(limit).Find(&users)
 
 	if result.Error != nil {
-		return nil, 0, result.Error
+		return nil, result.Error
 	}
 
-	return users, total, nil
+	return users, nil
 }
 
 // GetUsersByRole retrieves users by role
@@ -280,50 +340,23 @@ func (s *UserService) GetStudentsByClass(classLevel string) ([]models.User, erro
 
 // UpdateUserPhoto updates user profile photo
 func (s *UserService) UpdateUserPhoto(userID uint, photoPath string) error {
-	result := s.db.Model(&models.User{}).Where("id = ?", userID).Update("profile_photo", photoPath)
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
-	}
-
-	return nil
+	_, err := s.applyUpdate(userID, 0, map[string]interface{}{"profile_photo": photoPath})
+	return err
 }
 
-// DeactivateUser soft deletes user
+// DeactivateUser disables a user account without deleting it
 func (s *UserService) DeactivateUser(userID uint) error {
-	result := s.db.Model(&models.User{}).Where("id = ?", userID).Update("is_active", false)
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
-	}
-
-	return nil
+	_, err := s.applyUpdate(userID, 0, map[string]interface{}{"is_active": false})
+	return err
 }
 
 // ActivateUser reactivates user
 func (s *UserService) ActivateUser(userID uint) error {
-	result := s.db.Model(&models.User{}).Where("id = ?", userID).Update("is_active", true)
-
-	if result.Error != nil {
-		return result.Error
-	}
-
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
-	}
-
-	return nil
+	_, err := s.applyUpdate(userID, 0, map[string]interface{}{"is_active": true})
+	return err
 }
 
-// DeleteUser permanently deletes user (GORM soft delete)
+// DeleteUser moves user to the trash (GORM soft delete), see user_lifecycle.go
 func (s *UserService) DeleteUser(userID uint) error {
 	result := s.db.Delete(&models.User{}, userID)
 
@@ -332,7 +365,7 @@ func (s *UserService) DeleteUser(userID uint) error {
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return NotFoundError("user", "user not found")
 	}
 
 	return nil
@@ -380,32 +413,6 @@ func (s *UserService) GetUserStats() (map[string]int64, error) {
 	return stats, nil
 }
 
-// SearchUsers searches users by name, username, or email
-func (s *UserService) SearchUsers(query string, role string, limit int) ([]models.User, error) {
-	var users []models.User
-
-	db := s.db.Where("is_active = ?", true)
-
-	if role != "" {
-		db = db.Where("role = ?", role)
-	}
-
-	searchPattern := "%" + query + "%"
-	db = db.Where(
-		s.db.Where("full_name ILIKE ?", searchPattern).
-			Or("username ILIKE ?", searchPattern).
-			Or("email ILIKE ?", searchPattern),
-	)
-
-	result := db.Limit(limit).Find(&users)
-
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	return users, nil
-}
-
 // GetUserWithProfile gets user with complete profile based on role
 func (s *UserService) GetUserWithProfile(userID uint) (*models.User, error) {
 	var user models.User
@@ -413,7 +420,7 @@ func (s *UserService) GetUserWithProfile(userID uint) (*models.User, error) {
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found")
+			return nil, NotFoundError("user", "user not found")
 		}
 		return nil, result.Error
 	}
@@ -426,23 +433,23 @@ func (s *UserService) ValidateRoleRequiredFields(user *models.User) error {
 	switch user.Role {
 	case "teacher":
 		if user.EmployeeID == nil || *user.EmployeeID == "" {
-			return fmt.Errorf("employee_id is required for teachers")
+			return FieldValidationError("employee_id", "required", "employee_id is required for teachers")
 		}
 		if user.Specialization == nil || *user.Specialization == "" {
-			return fmt.Errorf("specialization is required for teachers")
+			return FieldValidationError("specialization", "required", "specialization is required for teachers")
 		}
 	case "student":
 		if user.StudentID == nil || *user.StudentID == "" {
-			return fmt.Errorf("student_id is required for students")
+			return FieldValidationError("student_id", "required", "student_id is required for students")
 		}
 		if user.ClassLevel == nil || *user.ClassLevel == "" {
-			return fmt.Errorf("class_level is required for students")
+			return FieldValidationError("class_level", "required", "class_level is required for students")
 		}
 		if user.ParentName == nil || *user.ParentName == "" {
-			return fmt.Errorf("parent_name is required for students")
+			return FieldValidationError("parent_name", "required", "parent_name is required for students")
 		}
 		if user.ParentPhone == nil || *user.ParentPhone == "" {
-			return fmt.Errorf("parent_phone is required for students")
+			return FieldValidationError("parent_phone", "required", "parent_phone is required for students")
 		}
 	case "admin":
 		// Admin doesn't require specific fields, but employee_id is recommended
@@ -450,25 +457,6 @@ func (s *UserService) ValidateRoleRequiredFields(user *models.User) error {
 	return nil
 }
 
-// BulkCreateUsers creates multiple users (useful for imports)
-func (s *UserService) BulkCreateUsers(users []models.User) error {
-	// Validate all users first
-	for i, user := range users {
-		if err := s.ValidateRoleRequiredFields(&user); err != nil {
-			return fmt.Errorf("validation failed for user %d: %v", i+1, err)
-		}
-	}
-
-	// Create all users in a transaction
-	result := s.db.CreateInBatches(&users, 100) // Process in batches of 100
-
-	if result.Error != nil {
-		return fmt.Errorf("bulk create failed: %v", result.Error)
-	}
-
-	return nil
-}
-
 // GetClassList returns list of all classes
 func (s *UserService) GetClassList() ([]string, error) {
 	var classes []string
@@ -0,0 +1,135 @@
+// user-service/services/user_version.go - Versioned updates and conflict detection
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"gitlab.com/nodiviti/user-service/models"
+)
+
+// VersionConflictError is returned when the caller's version is stale
+type VersionConflictError struct {
+	CurrentVersion uint
+	ChangedFields  []string
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("user was modified (current version %d)", e.CurrentVersion)
+}
+
+// applyUpdate writes updateData, bumps the version and records a revision.
+// If expectedVersion is non-zero the update only applies to that version.
+func (s *UserService) applyUpdate(userID uint, expectedVersion uint, updateData map[string]interface{}) (*models.User, error) {
+	var user models.User
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return applyUpdateTx(tx, &user, userID, expectedVersion, updateData)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// applyUpdateTx is applyUpdate within an existing transaction. It loads the
+// user into user and leaves the updated row there on success.
+func applyUpdateTx(tx *gorm.DB, user *models.User, userID uint, expectedVersion uint, updateData map[string]interface{}) error {
+	result := tx.First(user, userID)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return NotFoundError("user", "user not found")
+		}
+		return result.Error
+	}
+
+	if expectedVersion != 0 && user.Version != expectedVersion {
+		return versionConflict(tx, user, expectedVersion)
+	}
+
+	if len(updateData) == 0 {
+		return nil
+	}
+
+	before := user.ToResponse()
+	currentVersion := user.Version
+
+	updateData["version"] = gorm.Expr("version + 1")
+	result = tx.Model(user).Where("version = ?", currentVersion).Updates(updateData)
+	if result.Error != nil {
+		return translateDBError(result.Error, "failed to update user")
+	}
+	if result.RowsAffected == 0 {
+		// Lost a race with a concurrent writer between read and update
+		if err := tx.First(user, userID).Error; err != nil {
+			return err
+		}
+		return versionConflict(tx, user, currentVersion)
+	}
+
+	// Fetch updated user
+	if err := tx.First(user, userID).Error; err != nil {
+		return err
+	}
+
+	changed, _ := json.Marshal(models.DiffUserResponse(before, user.ToResponse()))
+	return tx.Create(&models.UserRevision{
+		UserID:        user.ID,
+		Version:       user.Version,
+		ChangedFields: string(changed),
+	}).Error
+}
+
+// versionConflict builds the conflict error with fields changed after sinceVersion
+func versionConflict(tx *gorm.DB, user *models.User, sinceVersion uint) error {
+	var revisions []models.UserRevision
+	tx.Where("user_id = ? AND version > ?", user.ID, sinceVersion).Find(&revisions)
+
+	seen := make(map[string]bool)
+	fields := []string{}
+	for _, revision := range revisions {
+		var changed []string
+		if err := json.Unmarshal([]byte(revision.ChangedFields), &changed); err != nil {
+			continue
+		}
+		for _, field := range changed {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+
+	return &VersionConflictError{
+		CurrentVersion: user.Version,
+		ChangedFields:  fields,
+	}
+}
+
+// PatchUser applies a JSON Merge Patch to a user.
+// The patched user must still satisfy the role's required fields.
+func (s *UserService) PatchUser(userID uint, patch *models.UserPatch, expectedVersion uint) (*models.User, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := *user
+	patch.ApplyTo(&patched)
+	if err := s.ValidateRoleRequiredFields(&patched); err != nil && s.ValidateRoleRequiredFields(user) == nil {
+		// Only reject when the patch breaks a previously valid user
+		return nil, err
+	}
+
+	updateData := make(map[string]interface{}, len(patch.Changes))
+	for column, value := range patch.Changes {
+		updateData[column] = value
+	}
+
+	return s.applyUpdate(userID, expectedVersion, updateData)
+}
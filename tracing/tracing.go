@@ -0,0 +1,94 @@
+// user-service/tracing/tracing.go - OpenTelemetry tracing setup
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+// ScopeName names the tracer of the service's own spans
+const ScopeName = "gitlab.com/nodiviti/user-service"
+
+// Exporters
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Tracer returns the tracer for the service's own spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+// Enabled reports whether cfg exports spans
+func Enabled(cfg *config.Config) bool {
+	return cfg.Tracing.Exporter != "" && cfg.Tracing.Exporter != ExporterNone
+}
+
+// Init installs the global tracer provider and W3C trace context propagator.
+// The returned shutdown flushes pending spans; it is a no-op when tracing is off.
+func Init(cfg *config.Config) (shutdown func(context.Context) error, err error) {
+	// Always propagate incoming traceparent headers, even when we export nothing
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !Enabled(cfg) {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// newExporter creates the span exporter named by cfg.Exporter
+func newExporter(cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case ExporterOTLP:
+		// Without an endpoint the OTEL_EXPORTER_OTLP_* variables apply
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %v", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (want otlp, stdout or none)", cfg.Exporter)
+	}
+}
@@ -1,11 +1,16 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"gitlab.com/nodiviti/user-service/config"
+	"gitlab.com/nodiviti/user-service/metrics"
 )
 
 type AuthClient struct {
@@ -28,16 +33,29 @@ func NewAuthClient(cfg *config.Config) *AuthClient {
 		baseURL: cfg.AuthService.URL,
 		httpClient: &http.Client{
 			Timeout: cfg.AuthService.Timeout,
+			// Traces each call and forwards the caller's traceparent
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
 
-// ValidateToken validates JWT token with auth service
-func (c *AuthClient) ValidateToken(token string) (*ValidateTokenResponse, error) {
+// ValidateToken validates JWT token with auth service. ctx carries the
+// incoming request's trace, which is propagated to the auth service.
+func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*ValidateTokenResponse, error) {
+	start := time.Now()
+	outcome := "error"
+	defer func() {
+		metrics.AuthRequestDuration.WithLabelValues("validate_token", outcome).Observe(time.Since(start).Seconds())
+	}()
+	fail := func(reason string) {
+		metrics.AuthRequestFailures.WithLabelValues("validate_token", reason).Inc()
+	}
+
 	url := fmt.Sprintf("%s/api/v1/auth/validate", c.baseURL)
 
-	req, err := http.NewRequest("POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
+		fail("request")
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
@@ -46,22 +64,54 @@ func (c *AuthClient) ValidateToken(token string) (*ValidateTokenResponse, error)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		fail("unreachable")
 		return nil, fmt.Errorf("failed to make request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		// A rejected token is an answer from the auth service, not a failure
+		if resp.StatusCode < http.StatusInternalServerError {
+			outcome = "rejected"
+		} else {
+			fail("server_error")
+		}
 		return nil, fmt.Errorf("invalid token: status %d", resp.StatusCode)
 	}
 
 	var validateResp ValidateTokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&validateResp); err != nil {
+		fail("decode")
 		return nil, fmt.Errorf("failed to decode response: %v", err)
 	}
 
+	outcome = "valid"
+	if !validateResp.Valid {
+		outcome = "rejected"
+	}
 	return &validateResp, nil
 }
 
+// Ping checks that the auth service is reachable. Any answer below 500
+// counts, since only reachability matters, not the health endpoint's body.
+func (c *AuthClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth service unreachable: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("auth service unhealthy: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // GetUserInfo gets basic user info from auth service
 func (c *AuthClient) GetUserInfo(userID int) (*ValidateTokenResponse, error) {
 	// This would be a separate endpoint in auth service to get user by ID
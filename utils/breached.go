@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bundledBreachedPasswords is an offline list of common/breached password hashes
+//
+//go:embed data/breached_passwords.txt
+var bundledBreachedPasswords string
+
+// BreachedPasswordChecker looks up password hashes using k-anonymity:
+// callers only reveal the first 5 hex characters of the SHA-1 hash and
+// receive every known 35-character suffix with its breach count.
+type BreachedPasswordChecker interface {
+	Range(prefix string) (map[string]int, error)
+}
+
+// LocalBreachedPasswords serves hash ranges from in-memory lists
+type LocalBreachedPasswords struct {
+	byPrefix map[string]map[string]int
+}
+
+// NewLocalBreachedPasswords loads the bundled list plus optional extra files.
+// Files use the Pwned Passwords "HASH:COUNT" line format; lines starting with # are ignored.
+func NewLocalBreachedPasswords(extraPaths ...string) (*LocalBreachedPasswords, error) {
+	l := &LocalBreachedPasswords{byPrefix: make(map[string]map[string]int)}
+
+	if err := l.load(strings.NewReader(bundledBreachedPasswords)); err != nil {
+		return nil, fmt.Errorf("failed to load bundled breached passwords: %v", err)
+	}
+
+	for _, path := range extraPaths {
+		if path == "" {
+			continue
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open breached password list: %v", err)
+		}
+		err = l.load(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load breached password list %s: %v", path, err)
+		}
+	}
+
+	return l, nil
+}
+
+// Range returns known hash suffixes for a 5 character SHA-1 prefix
+func (l *LocalBreachedPasswords) Range(prefix string) (map[string]int, error) {
+	if len(prefix) != 5 {
+		return nil, fmt.Errorf("hash prefix must be 5 characters")
+	}
+	return l.byPrefix[strings.ToUpper(prefix)], nil
+}
+
+func (l *LocalBreachedPasswords) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, countStr, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != 40 {
+			return fmt.Errorf("invalid line %q", line)
+		}
+
+		count := 1
+		if countStr != "" {
+			if n, err := strconv.Atoi(countStr); err == nil {
+				count = n
+			}
+		}
+
+		prefix, suffix := hash[:5], hash[5:]
+		if l.byPrefix[prefix] == nil {
+			l.byPrefix[prefix] = make(map[string]int)
+		}
+		l.byPrefix[prefix][suffix] += count
+	}
+	return scanner.Err()
+}
+
+var (
+	breachedChecker     BreachedPasswordChecker
+	breachedCheckerOnce sync.Once
+	breachedCheckerMu   sync.RWMutex
+)
+
+// SetBreachedPasswordChecker replaces the checker used by the password policy
+func SetBreachedPasswordChecker(checker BreachedPasswordChecker) {
+	breachedCheckerOnce.Do(func() {})
+	breachedCheckerMu.Lock()
+	defer breachedCheckerMu.Unlock()
+	breachedChecker = checker
+}
+
+// getBreachedPasswordChecker returns the configured checker, defaulting to the bundled list
+func getBreachedPasswordChecker() BreachedPasswordChecker {
+	breachedCheckerOnce.Do(func() {
+		if local, err := NewLocalBreachedPasswords(); err == nil {
+			breachedChecker = local
+		}
+	})
+
+	breachedCheckerMu.RLock()
+	defer breachedCheckerMu.RUnlock()
+	return breachedChecker
+}
+
+// PasswordBreachCount returns how often the password appears in the breached list
+func PasswordBreachCount(password string) (int, error) {
+	checker := getBreachedPasswordChecker()
+	if checker == nil {
+		return 0, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := checker.Range(hash[:5])
+	if err != nil {
+		return 0, err
+	}
+	return suffixes[hash[5:]], nil
+}
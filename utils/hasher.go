@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"gitlab.com/nodiviti/user-service/config"
+)
+
+// PasswordHasher hashes and verifies passwords in one encoded format.
+// The algorithm is identified by the prefix of the encoded hash, so hashes
+// from several hashers can coexist in the users table.
+type PasswordHasher interface {
+	// Name returns the algorithm name, e.g. "bcrypt" or "argon2id"
+	Name() string
+	// Hash returns the encoded hash of password
+	Hash(password string) (string, error)
+	// Matches reports whether encoded was produced by this hasher
+	Matches(encoded string) bool
+	// Verify compares password with an encoded hash of this hasher
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash reports whether encoded uses weaker parameters than the hasher
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher hashes passwords with bcrypt ($2a$, $2b$ or $2y$ prefix)
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher returns a bcrypt hasher, using the default cost when cost is 0
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+func (h *BcryptHasher) Name() string { return "bcrypt" }
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedBytes), nil
+}
+
+func (h *BcryptHasher) Matches(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.Cost
+}
+
+// Argon2idHasher hashes passwords with argon2id in the PHC string format:
+// $argon2id$v=19$m=<memory KiB>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type Argon2idHasher struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// NewArgon2idHasher returns an argon2id hasher, filling zero parameters with
+// the OWASP recommended minimums (19 MiB, 2 iterations, 1 lane)
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) *Argon2idHasher {
+	if memory == 0 {
+		memory = 19 * 1024
+	}
+	if iterations == 0 {
+		iterations = 2
+	}
+	if parallelism == 0 {
+		parallelism = 1
+	}
+	return &Argon2idHasher{
+		Memory:      memory,
+		Iterations:  iterations,
+		Parallelism: parallelism,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+func (h *Argon2idHasher) Name() string { return "argon2id" }
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Matches(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory < h.Memory ||
+		params.Iterations < h.Iterations ||
+		params.Parallelism < h.Parallelism ||
+		uint32(len(salt)) < h.SaltLength ||
+		uint32(len(key)) < h.KeyLength
+}
+
+// decodeArgon2id parses an argon2id PHC string
+func decodeArgon2id(encoded string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id hash: %v", err)
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id hash: %v", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id salt: %v", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id key: %v", err)
+	}
+
+	return params, salt, key, nil
+}
+
+// NewPasswordHasherFromConfig returns the hasher configured for new hashes
+func NewPasswordHasherFromConfig(cfg *config.Config) (PasswordHasher, error) {
+	switch cfg.Password.HashAlgorithm {
+	case "argon2id":
+		return NewArgon2idHasher(cfg.Password.Argon2Memory, cfg.Password.Argon2Iterations, cfg.Password.Argon2Parallelism), nil
+	case "bcrypt":
+		if cfg.Password.BcryptCost != 0 && (cfg.Password.BcryptCost < bcrypt.MinCost || cfg.Password.BcryptCost > bcrypt.MaxCost) {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return NewBcryptHasher(cfg.Password.BcryptCost), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q: expected argon2id or bcrypt", cfg.Password.HashAlgorithm)
+	}
+}
+
+var (
+	passwordHasher   PasswordHasher = NewArgon2idHasher(0, 0, 0)
+	legacyHashers                   = []PasswordHasher{NewBcryptHasher(0), NewArgon2idHasher(0, 0, 0)}
+	passwordHasherMu sync.RWMutex
+)
+
+// SetPasswordHasher replaces the hasher used for new hashes.
+// Hashes of the other supported algorithms can still be verified.
+func SetPasswordHasher(h PasswordHasher) {
+	passwordHasherMu.Lock()
+	defer passwordHasherMu.Unlock()
+	passwordHasher = h
+}
+
+// CurrentPasswordHasher returns the hasher used for new hashes
+func CurrentPasswordHasher() PasswordHasher {
+	passwordHasherMu.RLock()
+	defer passwordHasherMu.RUnlock()
+	return passwordHasher
+}
+
+// hasherFor returns the hasher that produced encoded
+func hasherFor(encoded string) PasswordHasher {
+	if current := CurrentPasswordHasher(); current.Matches(encoded) {
+		return current
+	}
+	for _, h := range legacyHashers {
+		if h.Matches(encoded) {
+			return h
+		}
+	}
+	return nil
+}
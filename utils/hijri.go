@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hijri conversion uses the arithmetical (tabular) Islamic calendar with the
+// common 2-5-7-10-13-16-18-21-24-26-29 leap-year cycle and the civil epoch
+// (1 Muharram 1 AH = 16 July 622 Julian). It is fully deterministic, but it may
+// differ by a day or two from dates fixed by rukyat or Kemenag's hisab.
+
+const islamicEpochJDN = 1948440 // JDN of 1 Muharram 1 AH
+
+// HijriMonthNames lists Hijri month names in Indonesian transliteration
+var HijriMonthNames = [12]string{
+	"Muharram", "Safar", "Rabiul Awal", "Rabiul Akhir",
+	"Jumadil Awal", "Jumadil Akhir", "Rajab", "Syaban",
+	"Ramadhan", "Syawal", "Dzulqaidah", "Dzulhijjah",
+}
+
+// HijriDate represents a date in the Hijri calendar
+type HijriDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// String returns the date in ISO-like form, e.g. "1446-01-01"
+func (d HijriDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MonthName returns the transliterated month name
+func (d HijriDate) MonthName() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return HijriMonthNames[d.Month-1]
+}
+
+// Format returns a human readable form, e.g. "1 Muharram 1446 H"
+func (d HijriDate) Format() string {
+	return fmt.Sprintf("%d %s %d H", d.Day, d.MonthName(), d.Year)
+}
+
+// IsHijriLeapYear reports whether the Hijri year has 355 days
+func IsHijriLeapYear(year int) bool {
+	return (14+11*year)%30 < 11
+}
+
+// HijriMonthLength returns the number of days in a Hijri month
+func HijriMonthLength(year, month int) int {
+	if month%2 == 1 || (month == 12 && IsHijriLeapYear(year)) {
+		return 30
+	}
+	return 29
+}
+
+// ToHijri converts a Gregorian date to the Hijri calendar.
+// Only the calendar date of t (in its own location) is used.
+func ToHijri(t time.Time) HijriDate {
+	jdn := gregorianToJDN(t.Year(), int(t.Month()), t.Day())
+
+	year := floorDiv(30*(jdn-islamicEpochJDN)+10646, 10631)
+	month := 1
+	for month < 12 && jdn >= hijriToJDN(year, month+1, 1) {
+		month++
+	}
+	day := jdn - hijriToJDN(year, month, 1) + 1
+
+	return HijriDate{Year: year, Month: month, Day: day}
+}
+
+// FromHijri converts a Hijri date to a Gregorian date at midnight UTC
+func FromHijri(year, month, day int) (time.Time, error) {
+	if year < 1 || month < 1 || month > 12 || day < 1 || day > HijriMonthLength(year, month) {
+		return time.Time{}, fmt.Errorf("invalid hijri date: %d-%02d-%02d", year, month, day)
+	}
+
+	y, m, d := jdnToGregorian(hijriToJDN(year, month, day))
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC), nil
+}
+
+// ParseHijriDate parses a Hijri date in "YYYY-MM-DD" form
+func ParseHijriDate(s string) (time.Time, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid hijri date %q: expected YYYY-MM-DD", s)
+	}
+
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid hijri date %q: expected YYYY-MM-DD", s)
+		}
+		nums[i] = n
+	}
+
+	return FromHijri(nums[0], nums[1], nums[2])
+}
+
+// HijriYearRange returns the Gregorian [start, end) range of a Hijri year
+func HijriYearRange(year int) (time.Time, time.Time) {
+	start, _ := FromHijri(year, 1, 1)
+	end, _ := FromHijri(year+1, 1, 1)
+	return start, end
+}
+
+// AcademicYear is a parsed academic year with its Gregorian [Start, End) range
+type AcademicYear struct {
+	Label    string    `json:"label"`
+	Calendar string    `json:"calendar"` // gregorian or hijri
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+}
+
+// Contains reports whether t falls inside the academic year
+func (a AcademicYear) Contains(t time.Time) bool {
+	return !t.Before(a.Start) && t.Before(a.End)
+}
+
+var (
+	gregorianYearPattern = regexp.MustCompile(`^(\d{4})(?:\s*/\s*(\d{4}))?$`)
+	hijriYearPattern     = regexp.MustCompile(`(?i)^(\d{4})(?:\s*/\s*(\d{4}))?\s*(?:H|AH)$`)
+)
+
+// ParseAcademicYear parses a Gregorian or Hijri academic year.
+//
+// Supported forms:
+//   - "2024"       Gregorian calendar year (1 Jan - 1 Jan)
+//   - "2024/2025"  Gregorian school year (1 Jul 2024 - 1 Jul 2025)
+//   - "1446H"      Hijri calendar year (1 Muharram - 1 Muharram)
+//   - "1445/1446H" Pesantren year (1 Syawal 1445 - 1 Syawal 1446)
+func ParseAcademicYear(s string) (AcademicYear, error) {
+	s = strings.TrimSpace(s)
+
+	if m := hijriYearPattern.FindStringSubmatch(s); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		if m[2] == "" {
+			start, end := HijriYearRange(first)
+			return AcademicYear{Label: fmt.Sprintf("%dH", first), Calendar: "hijri", Start: start, End: end}, nil
+		}
+
+		second, _ := strconv.Atoi(m[2])
+		if second != first+1 {
+			return AcademicYear{}, fmt.Errorf("invalid academic year %q: years must be consecutive", s)
+		}
+		start, _ := FromHijri(first, 10, 1)
+		end, _ := FromHijri(second, 10, 1)
+		return AcademicYear{Label: fmt.Sprintf("%d/%dH", first, second), Calendar: "hijri", Start: start, End: end}, nil
+	}
+
+	if m := gregorianYearPattern.FindStringSubmatch(s); m != nil {
+		first, _ := strconv.Atoi(m[1])
+		if m[2] == "" {
+			start := time.Date(first, time.January, 1, 0, 0, 0, 0, time.UTC)
+			return AcademicYear{Label: m[1], Calendar: "gregorian", Start: start, End: start.AddDate(1, 0, 0)}, nil
+		}
+
+		second, _ := strconv.Atoi(m[2])
+		if second != first+1 {
+			return AcademicYear{}, fmt.Errorf("invalid academic year %q: years must be consecutive", s)
+		}
+		start := time.Date(first, time.July, 1, 0, 0, 0, 0, time.UTC)
+		return AcademicYear{Label: fmt.Sprintf("%d/%d", first, second), Calendar: "gregorian", Start: start, End: start.AddDate(1, 0, 0)}, nil
+	}
+
+	return AcademicYear{}, fmt.Errorf("invalid academic year %q: expected e.g. 2024/2025 or 1445/1446H", s)
+}
+
+// hijriToJDN returns the Julian Day Number of a tabular Hijri date
+func hijriToJDN(year, month, day int) int {
+	return day +
+		ceilHalfMonths(month-1) +
+		(year-1)*354 +
+		floorDiv(3+11*year, 30) +
+		islamicEpochJDN - 1
+}
+
+// ceilHalfMonths returns ceil(29.5 * n) for n >= 0
+func ceilHalfMonths(n int) int {
+	return (59*n + 1) / 2
+}
+
+// gregorianToJDN returns the Julian Day Number of a proleptic Gregorian date
+func gregorianToJDN(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+// jdnToGregorian converts a Julian Day Number to a proleptic Gregorian date
+func jdnToGregorian(jdn int) (int, int, int) {
+	a := jdn + 32044
+	b := (4*a + 3) / 146097
+	c := a - 146097*b/4
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+
+	day := e - (153*m+2)/5 + 1
+	month := m + 3 - 12*(m/10)
+	year := 100*b + d - 4800 + m/10
+	return year, month, day
+}
+
+// floorDiv performs integer division rounding towards negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Pairs from tabular (civil epoch) Islamic calendar tables. Observed dates
+// may differ by a day: Kemenag set 1 Muharram 1446 on 2024-07-07, while
+// 1445 is a leap year of the tabular cycle, which puts it on 2024-07-08.
+var hijriTable = []struct {
+	gregorian time.Time
+	hijri     HijriDate
+}{
+	{date(622, time.July, 19), HijriDate{1, 1, 1}}, // Julian 16 July 622
+	{date(2000, time.January, 1), HijriDate{1420, 9, 24}},
+	{date(2023, time.July, 19), HijriDate{1445, 1, 1}},
+	{date(2024, time.March, 11), HijriDate{1445, 9, 1}},
+	{date(2024, time.April, 10), HijriDate{1445, 10, 1}},
+	{date(2024, time.July, 7), HijriDate{1445, 12, 30}},
+	{date(2024, time.July, 8), HijriDate{1446, 1, 1}},
+	{date(2025, time.March, 31), HijriDate{1446, 10, 1}},
+}
+
+func TestToHijri(t *testing.T) {
+	for _, tt := range hijriTable {
+		if got := ToHijri(tt.gregorian); got != tt.hijri {
+			t.Errorf("ToHijri(%s) = %s, want %s", tt.gregorian.Format("2006-01-02"), got, tt.hijri)
+		}
+	}
+}
+
+func TestFromHijri(t *testing.T) {
+	for _, tt := range hijriTable {
+		got, err := FromHijri(tt.hijri.Year, tt.hijri.Month, tt.hijri.Day)
+		if err != nil {
+			t.Errorf("FromHijri(%s): %v", tt.hijri, err)
+			continue
+		}
+		if !got.Equal(tt.gregorian) {
+			t.Errorf("FromHijri(%s) = %s, want %s", tt.hijri, got.Format("2006-01-02"), tt.gregorian.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestFromHijriRejectsInvalidDates(t *testing.T) {
+	for _, d := range []HijriDate{{0, 1, 1}, {1445, 0, 1}, {1445, 13, 1}, {1445, 2, 30}, {1446, 12, 30}} {
+		if _, err := FromHijri(d.Year, d.Month, d.Day); err == nil {
+			t.Errorf("FromHijri(%s) succeeded, want an error", d)
+		}
+	}
+}
+
+func TestHijriLeapYears(t *testing.T) {
+	// Years 2, 5, 7, 10, 13, 16, 18, 21, 24, 26 and 29 of each 30-year cycle
+	leap := map[int]bool{2: true, 5: true, 7: true, 10: true, 13: true, 16: true, 18: true, 21: true, 24: true, 26: true, 29: true}
+	for year := 1421; year <= 1470; year++ {
+		want := leap[year%30]
+		if got := IsHijriLeapYear(year); got != want {
+			t.Errorf("IsHijriLeapYear(%d) = %v, want %v", year, got, want)
+		}
+
+		start, end := HijriYearRange(year)
+		days := int(end.Sub(start).Hours() / 24)
+		wantDays := 354
+		if want {
+			wantDays = 355
+		}
+		if days != wantDays {
+			t.Errorf("year %d has %d days, want %d", year, days, wantDays)
+		}
+		if got := HijriMonthLength(year, 12); got != wantDays-325 {
+			t.Errorf("Dzulhijjah %d has %d days, want %d", year, got, wantDays-325)
+		}
+	}
+}
+
+func TestHijriRoundTrip(t *testing.T) {
+	prev := ToHijri(date(1999, time.December, 31))
+	for d := date(2000, time.January, 1); d.Year() < 2100; d = d.AddDate(0, 0, 1) {
+		h := ToHijri(d)
+		back, err := FromHijri(h.Year, h.Month, h.Day)
+		if err != nil || !back.Equal(d) {
+			t.Fatalf("%s -> %s -> %s (%v)", d.Format("2006-01-02"), h, back.Format("2006-01-02"), err)
+		}
+
+		// Consecutive days are consecutive Hijri dates
+		if h.Day != prev.Day+1 && !(h.Day == 1 && prev.Day == HijriMonthLength(prev.Year, prev.Month)) {
+			t.Fatalf("%s follows %s", h, prev)
+		}
+		prev = h
+	}
+}
+
+func TestParseHijriDate(t *testing.T) {
+	got, err := ParseHijriDate(" 1446-01-01 ")
+	if err != nil || !got.Equal(date(2024, time.July, 8)) {
+		t.Errorf("ParseHijriDate = %s, %v", got, err)
+	}
+	for _, s := range []string{"1446-01", "1446/01/01", "1446-13-01", "x-01-01"} {
+		if _, err := ParseHijriDate(s); err == nil {
+			t.Errorf("ParseHijriDate(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestParseAcademicYear(t *testing.T) {
+	tests := []struct {
+		in       string
+		label    string
+		calendar string
+		start    time.Time
+		end      time.Time
+	}{
+		{"2024", "2024", "gregorian", date(2024, time.January, 1), date(2025, time.January, 1)},
+		{"2024/2025", "2024/2025", "gregorian", date(2024, time.July, 1), date(2025, time.July, 1)},
+		{"2024 / 2025", "2024/2025", "gregorian", date(2024, time.July, 1), date(2025, time.July, 1)},
+		{"1446H", "1446H", "hijri", date(2024, time.July, 8), date(2025, time.June, 27)},
+		{"1446 AH", "1446H", "hijri", date(2024, time.July, 8), date(2025, time.June, 27)},
+		{"1445/1446H", "1445/1446H", "hijri", date(2024, time.April, 10), date(2025, time.March, 31)},
+	}
+	for _, tt := range tests {
+		got, err := ParseAcademicYear(tt.in)
+		if err != nil {
+			t.Errorf("ParseAcademicYear(%q): %v", tt.in, err)
+			continue
+		}
+		if got.Label != tt.label || got.Calendar != tt.calendar || !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) {
+			t.Errorf("ParseAcademicYear(%q) = %+v, want %s %s [%s, %s)", tt.in, got, tt.label, tt.calendar,
+				tt.start.Format("2006-01-02"), tt.end.Format("2006-01-02"))
+		}
+	}
+
+	for _, s := range []string{"", "24/25", "2024/2026", "1445/1447H", "1446X", "abcd"} {
+		if _, err := ParseAcademicYear(s); err == nil {
+			t.Errorf("ParseAcademicYear(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestAcademicYearContains(t *testing.T) {
+	year, _ := ParseAcademicYear("2024/2025")
+	if !year.Contains(date(2024, time.July, 1)) || !year.Contains(date(2025, time.June, 30)) {
+		t.Error("academic year should contain its first and last day")
+	}
+	if year.Contains(date(2025, time.July, 1)) || year.Contains(date(2024, time.June, 30)) {
+		t.Error("academic year should not contain days outside it")
+	}
+}
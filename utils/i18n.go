@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a response language supported by the message catalog
+type Language string
+
+const (
+	English    Language = "en"
+	Indonesian Language = "id"
+	Arabic     Language = "ar"
+)
+
+// DefaultLanguage is used when the client does not ask for a supported language
+const DefaultLanguage = English
+
+// SupportedLanguages lists every language of the catalog
+var SupportedLanguages = []Language{English, Indonesian, Arabic}
+
+// languageAliases maps language subtags to a supported language
+var languageAliases = map[string]Language{
+	"en": English,
+	"id": Indonesian,
+	"in": Indonesian, // Legacy code for Indonesian, still sent by older Android versions
+	"ar": Arabic,
+}
+
+// ParseAcceptLanguage returns the supported language the client prefers most,
+// e.g. "id-ID,id;q=0.9,en;q=0.8" -> Indonesian
+func ParseAcceptLanguage(header string) Language {
+	type candidate struct {
+		lang    Language
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		lang, ok := languageAliases[primary]
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, quality: quality})
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// Message returns the catalog text of code in lang with {name} placeholders
+// filled from params. ok is false if the catalog has no such message or
+// params leave a placeholder empty.
+func Message(lang Language, code string, params map[string]string) (string, bool) {
+	text, ok := messages[lang][code]
+	if !ok {
+		return "", false
+	}
+
+	for name, value := range params {
+		if value == "" {
+			continue
+		}
+		text = strings.ReplaceAll(text, "{"+name+"}", value)
+	}
+	if strings.Contains(text, "{") {
+		return "", false
+	}
+	return text, true
+}
+
+// Localize returns the catalog text of code in lang. Codes the language does
+// not translate fall back to fallback, the English message the code was raised
+// with, and then to the English catalog.
+func Localize(lang Language, code string, params map[string]string, fallback string) string {
+	if text, ok := Message(lang, code, params); ok {
+		return text
+	}
+	if fallback != "" {
+		return fallback
+	}
+	if text, ok := Message(English, code, params); ok {
+		return text
+	}
+	return code
+}
@@ -0,0 +1,329 @@
+package utils
+
+// messages is the message catalog, keyed by language and then by the stable
+// code of an error, success message, password violation or feedback item.
+// Placeholders such as {field} are filled by Message.
+//
+// English entries are only given where a code always has the same text; codes
+// raised with a more specific English message keep that message in English.
+// Field error messages use the code prefixed with "field_".
+var messages = map[Language]map[string]string{
+	English: {
+		// Errors
+		"invalid_request":          "Invalid request format",
+		"user_not_found":           "User not found",
+		"cursor_sort_mismatch":     "Cursor does not match the requested sort",
+		"class_level_required":     "Class level is required",
+		"query_required":           "Search query is required",
+		"file_required":            "No file uploaded",
+		"password_policy":          "Password does not meet requirements",
+		"if_match_required":        "If-Match header required",
+		"if_match_wildcard":        "If-Match must carry the user's ETag, not *",
+		"invalid_if_match":         "Invalid If-Match header",
+		"if_match_mismatch":        "If-Match does not refer to this user",
+		"version_conflict":         "User was modified by someone else",
+		"unsupported_media_type":   "Content-Type must be application/merge-patch+json",
+		"self_service_denied":      "Some fields cannot be changed on your own profile",
+		"self_service_hint":        "Submit fields that require approval to POST /api/v1/users/me/change-requests",
+		"conflict":                 "{field} is already used by another user",
+		"change_request_not_found": "Change request not found",
+		"change_request_stale":     "The profile changed after this request was submitted ({fields}), submit a new request",
+		"concurrent_update":        "The user was changed at the same time, try again",
+		"trashed_user_not_found":   "User not found in trash",
+		"rate_limited":             "Too many requests, try again in {retry_after} seconds",
+		"own_change_request":       "Cannot review your own change request",
+		"review_not_allowed":       "Not allowed to review this change request",
+		"read_only_field":          "{field} is read-only",
+		"retention_period_active":  "User cannot be purged before {purge_after}",
+		"setup_not_available":      "Setup is not available",
+		"invalid_setup_token":      "Invalid setup token",
+		"password_change_required": "Change your temporary password with POST /api/v1/users/me/password before continuing",
+
+		// Field errors
+		"field_read_only":         "{field} cannot be changed on your own profile",
+		"field_requires_approval": "{field} cannot be changed on your own profile",
+
+		// Success messages
+		"profile_retrieved":           "Profile retrieved successfully",
+		"profile_updated":             "Profile updated successfully",
+		"user_profile_retrieved":      "User profile retrieved successfully",
+		"users_retrieved":             "Users retrieved successfully",
+		"user_created":                "User created successfully",
+		"user_updated":                "User updated successfully",
+		"user_deactivated":            "User deactivated successfully",
+		"teachers_retrieved":          "Teachers retrieved successfully",
+		"students_retrieved":          "Students retrieved successfully",
+		"class_list_retrieved":        "Class list retrieved successfully",
+		"user_statistics_retrieved":   "User statistics retrieved successfully",
+		"config_retrieved":            "Configuration retrieved successfully",
+		"search_completed":            "Search completed successfully",
+		"profile_photo_updated":       "Profile photo updated successfully",
+		"password_changed":            "Password changed successfully",
+		"password_verified":           "Password verified",
+		"password_reset":              "Password reset successfully",
+		"password_strength_evaluated": "Password strength evaluated",
+		"change_request_submitted":    "Change request submitted for approval",
+		"change_requests_retrieved":   "Change requests retrieved successfully",
+		"change_request_cancelled":    "Change request cancelled",
+		"change_request_approved":     "Change request approved",
+		"change_request_rejected":     "Change request rejected",
+		"editable_fields_retrieved":   "Editable fields retrieved successfully",
+		"user_activated":              "User activated successfully",
+		"user_trashed":                "User moved to trash",
+		"trash_retrieved":             "Trash retrieved successfully",
+		"user_restored":               "User restored successfully",
+		"user_purged":                 "User permanently deleted",
+		"expired_users_purged":        "Expired users permanently deleted",
+		"setup_status_retrieved":      "Setup status retrieved",
+		"setup_completed":             "Setup completed, admin user created",
+
+		// Password requirements
+		"password_too_short":         "at least {min} characters long",
+		"password_too_long":          "at most {max} characters long",
+		"password_missing_upper":     "at least one uppercase letter",
+		"password_missing_lower":     "at least one lowercase letter",
+		"password_missing_digit":     "at least one number",
+		"password_missing_special":   "at least one special character (!@#$%^&*)",
+		"password_contains_username": "must not contain your username",
+		"password_contains_email":    "must not contain your email",
+		"password_contains_name":     "must not contain your name",
+		"password_breached":          "must not be a common or breached password",
+
+		// Password strength
+		"strength_very_weak":      "Very Weak",
+		"strength_weak":           "Weak",
+		"strength_medium":         "Medium",
+		"strength_strong":         "Strong",
+		"strength_very_strong":    "Very Strong",
+		"feedback_length":         "increase length to 8+ characters",
+		"feedback_lowercase":      "add lowercase letters",
+		"feedback_uppercase":      "add uppercase letters",
+		"feedback_digit":          "add numbers",
+		"feedback_special":        "add special characters",
+		"feedback_longer":         "use 12+ characters for a stronger password",
+		"feedback_breached":       "this password appears in breached password lists",
+		"feedback_common_pattern": "avoid common patterns",
+	},
+
+	Indonesian: {
+		// Errors
+		"invalid_request":          "Format permintaan tidak valid",
+		"invalid_id":               "ID tidak valid",
+		"unauthorized":             "Autentikasi diperlukan",
+		"user_not_found":           "Pengguna tidak ditemukan",
+		"cursor_sort_mismatch":     "Kursor tidak sesuai dengan urutan yang diminta",
+		"class_level_required":     "Kelas wajib diisi",
+		"query_required":           "Kata kunci pencarian wajib diisi",
+		"file_required":            "Tidak ada berkas yang diunggah",
+		"invalid_file":             "Berkas tidak valid",
+		"internal_error":           "Terjadi kesalahan pada server",
+		"rate_limited":             "Terlalu banyak permintaan, coba lagi dalam {retry_after} detik",
+		"validation_failed":        "Validasi gagal",
+		"password_policy":          "Kata sandi tidak memenuhi persyaratan",
+		"incorrect_password":       "Kata sandi salah",
+		"if_match_required":        "Header If-Match wajib disertakan",
+		"if_match_wildcard":        "If-Match harus berisi ETag pengguna, bukan *",
+		"invalid_if_match":         "Header If-Match tidak valid",
+		"if_match_mismatch":        "If-Match tidak merujuk ke pengguna ini",
+		"version_conflict":         "Data pengguna telah diubah oleh orang lain",
+		"unsupported_media_type":   "Content-Type harus application/merge-patch+json",
+		"self_service_denied":      "Beberapa kolom tidak dapat diubah pada profil Anda sendiri",
+		"self_service_hint":        "Ajukan kolom yang memerlukan persetujuan ke POST /api/v1/users/me/change-requests",
+		"conflict":                 "{field} sudah digunakan oleh pengguna lain",
+		"change_request_not_found": "Permintaan perubahan tidak ditemukan",
+		"change_request_closed":    "Permintaan perubahan sudah tidak menunggu peninjauan",
+		"change_request_stale":     "Profil telah berubah setelah permintaan ini diajukan ({fields}), ajukan permintaan baru",
+		"concurrent_update":        "Pengguna diubah pada saat yang sama, coba lagi",
+		"trashed_user_not_found":   "Pengguna tidak ditemukan di tempat sampah",
+		"own_change_request":       "Tidak dapat meninjau permintaan perubahan Anda sendiri",
+		"review_not_allowed":       "Tidak diizinkan meninjau permintaan perubahan ini",
+		"read_only_field":          "{field} hanya dapat dibaca",
+		"retention_period_active":  "Pengguna tidak dapat dihapus permanen sebelum {purge_after}",
+		"setup_not_available":      "Penyiapan tidak tersedia",
+		"invalid_setup_token":      "Token penyiapan tidak valid",
+		"password_change_required": "Ganti kata sandi sementara Anda melalui POST /api/v1/users/me/password sebelum melanjutkan",
+		"invalid_token":            "Token tidak valid",
+		"forbidden":                "Izin tidak mencukupi",
+
+		// Field errors
+		"field_required":          "{field} wajib diisi",
+		"field_invalid":           "{field} tidak valid",
+		"field_duplicate":         "{field} berisi nilai ganda",
+		"field_not_clearable":     "{field} tidak dapat dikosongkan melalui permintaan perubahan",
+		"field_password_reused":   "Kata sandi baru harus berbeda dari kata sandi sebelumnya",
+		"field_read_only":         "{field} tidak dapat diubah pada profil Anda sendiri",
+		"field_requires_approval": "{field} memerlukan persetujuan admin melalui permintaan perubahan",
+
+		// Success messages
+		"profile_retrieved":           "Profil berhasil diambil",
+		"profile_updated":             "Profil berhasil diperbarui",
+		"user_profile_retrieved":      "Profil pengguna berhasil diambil",
+		"users_retrieved":             "Daftar pengguna berhasil diambil",
+		"user_created":                "Pengguna berhasil dibuat",
+		"user_updated":                "Pengguna berhasil diperbarui",
+		"user_deactivated":            "Pengguna berhasil dinonaktifkan",
+		"teachers_retrieved":          "Daftar ustadz berhasil diambil",
+		"students_retrieved":          "Daftar santri berhasil diambil",
+		"class_list_retrieved":        "Daftar kelas berhasil diambil",
+		"user_statistics_retrieved":   "Statistik pengguna berhasil diambil",
+		"config_retrieved":            "Konfigurasi berhasil diambil",
+		"search_completed":            "Pencarian selesai",
+		"profile_photo_updated":       "Foto profil berhasil diperbarui",
+		"password_changed":            "Kata sandi berhasil diubah",
+		"password_verified":           "Kata sandi terverifikasi",
+		"password_reset":              "Kata sandi berhasil diatur ulang",
+		"password_strength_evaluated": "Kekuatan kata sandi telah dinilai",
+		"change_request_submitted":    "Permintaan perubahan telah diajukan untuk persetujuan",
+		"change_requests_retrieved":   "Permintaan perubahan berhasil diambil",
+		"change_request_cancelled":    "Permintaan perubahan dibatalkan",
+		"change_request_approved":     "Permintaan perubahan disetujui",
+		"change_request_rejected":     "Permintaan perubahan ditolak",
+		"editable_fields_retrieved":   "Kolom yang dapat diubah berhasil diambil",
+		"user_activated":              "Pengguna berhasil diaktifkan",
+		"user_trashed":                "Pengguna dipindahkan ke tempat sampah",
+		"trash_retrieved":             "Tempat sampah berhasil diambil",
+		"user_restored":               "Pengguna berhasil dipulihkan",
+		"user_purged":                 "Pengguna dihapus permanen",
+		"expired_users_purged":        "Pengguna kedaluwarsa dihapus permanen",
+		"setup_status_retrieved":      "Status penyiapan berhasil diambil",
+		"setup_completed":             "Penyiapan selesai, pengguna admin telah dibuat",
+
+		// Password requirements
+		"password_too_short":         "minimal {min} karakter",
+		"password_too_long":          "maksimal {max} karakter",
+		"password_missing_upper":     "minimal satu huruf besar",
+		"password_missing_lower":     "minimal satu huruf kecil",
+		"password_missing_digit":     "minimal satu angka",
+		"password_missing_special":   "minimal satu karakter khusus (!@#$%^&*)",
+		"password_contains_username": "tidak boleh mengandung nama pengguna Anda",
+		"password_contains_email":    "tidak boleh mengandung email Anda",
+		"password_contains_name":     "tidak boleh mengandung nama Anda",
+		"password_breached":          "tidak boleh berupa kata sandi umum atau yang pernah bocor",
+
+		// Password strength
+		"strength_very_weak":      "Sangat Lemah",
+		"strength_weak":           "Lemah",
+		"strength_medium":         "Sedang",
+		"strength_strong":         "Kuat",
+		"strength_very_strong":    "Sangat Kuat",
+		"feedback_length":         "perpanjang menjadi 8 karakter atau lebih",
+		"feedback_lowercase":      "tambahkan huruf kecil",
+		"feedback_uppercase":      "tambahkan huruf besar",
+		"feedback_digit":          "tambahkan angka",
+		"feedback_special":        "tambahkan karakter khusus",
+		"feedback_longer":         "gunakan 12 karakter atau lebih agar kata sandi lebih kuat",
+		"feedback_breached":       "kata sandi ini terdapat dalam daftar kata sandi yang bocor",
+		"feedback_common_pattern": "hindari pola yang umum",
+	},
+
+	Arabic: {
+		// Errors
+		"invalid_request":          "صيغة الطلب غير صالحة",
+		"invalid_id":               "المعرّف غير صالح",
+		"unauthorized":             "المصادقة مطلوبة",
+		"user_not_found":           "المستخدم غير موجود",
+		"cursor_sort_mismatch":     "المؤشر لا يطابق الترتيب المطلوب",
+		"class_level_required":     "الصف الدراسي مطلوب",
+		"query_required":           "عبارة البحث مطلوبة",
+		"file_required":            "لم يتم رفع أي ملف",
+		"invalid_file":             "الملف غير صالح",
+		"internal_error":           "حدث خطأ في الخادم",
+		"rate_limited":             "طلبات كثيرة جدًا، حاول مرة أخرى بعد {retry_after} ثانية",
+		"validation_failed":        "فشل التحقق من صحة البيانات",
+		"password_policy":          "كلمة المرور لا تستوفي المتطلبات",
+		"incorrect_password":       "كلمة المرور غير صحيحة",
+		"if_match_required":        "ترويسة If-Match مطلوبة",
+		"if_match_wildcard":        "يجب أن تحتوي ترويسة If-Match على ETag المستخدم وليس *",
+		"invalid_if_match":         "ترويسة If-Match غير صالحة",
+		"if_match_mismatch":        "If-Match لا يشير إلى هذا المستخدم",
+		"version_conflict":         "تم تعديل المستخدم من قبل شخص آخر",
+		"unsupported_media_type":   "يجب أن يكون Content-Type هو application/merge-patch+json",
+		"self_service_denied":      "لا يمكن تعديل بعض الحقول في ملفك الشخصي",
+		"self_service_hint":        "أرسل الحقول التي تتطلب موافقة إلى POST /api/v1/users/me/change-requests",
+		"conflict":                 "{field} مستخدم بالفعل من قبل مستخدم آخر",
+		"change_request_not_found": "طلب التغيير غير موجود",
+		"change_request_closed":    "طلب التغيير لم يعد قيد المراجعة",
+		"change_request_stale":     "تغير الملف الشخصي بعد تقديم هذا الطلب ({fields})، يرجى تقديم طلب جديد",
+		"concurrent_update":        "تم تعديل المستخدم في الوقت نفسه، يرجى المحاولة مرة أخرى",
+		"trashed_user_not_found":   "المستخدم غير موجود في سلة المحذوفات",
+		"own_change_request":       "لا يمكنك مراجعة طلب التغيير الخاص بك",
+		"review_not_allowed":       "غير مسموح لك بمراجعة طلب التغيير هذا",
+		"read_only_field":          "{field} للقراءة فقط",
+		"retention_period_active":  "لا يمكن حذف المستخدم نهائيًا قبل {purge_after}",
+		"setup_not_available":      "الإعداد غير متاح",
+		"invalid_setup_token":      "رمز الإعداد غير صالح",
+		"password_change_required": "يرجى تغيير كلمة المرور المؤقتة عبر POST /api/v1/users/me/password قبل المتابعة",
+		"invalid_token":            "الرمز غير صالح",
+		"forbidden":                "ليست لديك صلاحيات كافية",
+
+		// Field errors
+		"field_required":          "{field} مطلوب",
+		"field_invalid":           "{field} غير صالح",
+		"field_duplicate":         "{field} يحتوي على قيمة مكررة",
+		"field_not_clearable":     "لا يمكن مسح {field} عبر طلب تغيير",
+		"field_password_reused":   "يجب أن تختلف كلمة المرور الجديدة عن كلمات المرور السابقة",
+		"field_read_only":         "لا يمكن تعديل {field} في ملفك الشخصي",
+		"field_requires_approval": "يتطلب تعديل {field} موافقة المسؤول عبر طلب تغيير",
+
+		// Success messages
+		"profile_retrieved":           "تم جلب الملف الشخصي بنجاح",
+		"profile_updated":             "تم تحديث الملف الشخصي بنجاح",
+		"user_profile_retrieved":      "تم جلب ملف المستخدم بنجاح",
+		"users_retrieved":             "تم جلب المستخدمين بنجاح",
+		"user_created":                "تم إنشاء المستخدم بنجاح",
+		"user_updated":                "تم تحديث المستخدم بنجاح",
+		"user_deactivated":            "تم تعطيل المستخدم بنجاح",
+		"teachers_retrieved":          "تم جلب الأساتذة بنجاح",
+		"students_retrieved":          "تم جلب الطلاب بنجاح",
+		"class_list_retrieved":        "تم جلب قائمة الصفوف بنجاح",
+		"user_statistics_retrieved":   "تم جلب إحصاءات المستخدمين بنجاح",
+		"config_retrieved":            "تم جلب الإعدادات بنجاح",
+		"search_completed":            "اكتمل البحث بنجاح",
+		"profile_photo_updated":       "تم تحديث صورة الملف الشخصي بنجاح",
+		"password_changed":            "تم تغيير كلمة المرور بنجاح",
+		"password_verified":           "تم التحقق من كلمة المرور",
+		"password_reset":              "تمت إعادة تعيين كلمة المرور بنجاح",
+		"password_strength_evaluated": "تم تقييم قوة كلمة المرور",
+		"change_request_submitted":    "تم تقديم طلب التغيير للموافقة",
+		"change_requests_retrieved":   "تم جلب طلبات التغيير بنجاح",
+		"change_request_cancelled":    "تم إلغاء طلب التغيير",
+		"change_request_approved":     "تمت الموافقة على طلب التغيير",
+		"change_request_rejected":     "تم رفض طلب التغيير",
+		"editable_fields_retrieved":   "تم جلب الحقول القابلة للتعديل بنجاح",
+		"user_activated":              "تم تفعيل المستخدم بنجاح",
+		"user_trashed":                "تم نقل المستخدم إلى سلة المحذوفات",
+		"trash_retrieved":             "تم جلب سلة المحذوفات بنجاح",
+		"user_restored":               "تمت استعادة المستخدم بنجاح",
+		"user_purged":                 "تم حذف المستخدم نهائيًا",
+		"expired_users_purged":        "تم حذف المستخدمين المنتهية مدتهم نهائيًا",
+		"setup_status_retrieved":      "تم جلب حالة الإعداد",
+		"setup_completed":             "اكتمل الإعداد وتم إنشاء المستخدم المسؤول",
+
+		// Password requirements
+		"password_too_short":         "{min} أحرف على الأقل",
+		"password_too_long":          "{max} حرفًا كحد أقصى",
+		"password_missing_upper":     "حرف كبير واحد على الأقل",
+		"password_missing_lower":     "حرف صغير واحد على الأقل",
+		"password_missing_digit":     "رقم واحد على الأقل",
+		"password_missing_special":   "رمز خاص واحد على الأقل (!@#$%^&*)",
+		"password_contains_username": "يجب ألا تحتوي على اسم المستخدم الخاص بك",
+		"password_contains_email":    "يجب ألا تحتوي على بريدك الإلكتروني",
+		"password_contains_name":     "يجب ألا تحتوي على اسمك",
+		"password_breached":          "يجب ألا تكون كلمة مرور شائعة أو مسربة",
+
+		// Password strength
+		"strength_very_weak":      "ضعيفة جدًا",
+		"strength_weak":           "ضعيفة",
+		"strength_medium":         "متوسطة",
+		"strength_strong":         "قوية",
+		"strength_very_strong":    "قوية جدًا",
+		"feedback_length":         "زد الطول إلى 8 أحرف أو أكثر",
+		"feedback_lowercase":      "أضف أحرفًا صغيرة",
+		"feedback_uppercase":      "أضف أحرفًا كبيرة",
+		"feedback_digit":          "أضف أرقامًا",
+		"feedback_special":        "أضف رموزًا خاصة",
+		"feedback_longer":         "استخدم 12 حرفًا أو أكثر لكلمة مرور أقوى",
+		"feedback_breached":       "تظهر كلمة المرور هذه في قوائم كلمات المرور المسربة",
+		"feedback_common_pattern": "تجنب الأنماط الشائعة",
+	},
+}
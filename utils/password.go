@@ -1,40 +1,140 @@
 package utils
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"unicode"
 
-	"golang.org/x/crypto/bcrypt"
+	"gitlab.com/nodiviti/user-service/config"
 )
 
 // PasswordRequirements defines password validation rules
 type PasswordRequirements struct {
-	MinLength      int
-	RequireUpper   bool
-	RequireLower   bool
-	RequireDigit   bool
-	RequireSpecial bool
+	MinLength          int
+	MaxLength          int // 0 means no limit
+	RequireUpper       bool
+	RequireLower       bool
+	RequireDigit       bool
+	RequireSpecial     bool
+	RejectPersonalInfo bool // Reject passwords containing the username, email or name
+	RejectBreached     bool // Reject passwords found in the breached password list
 }
 
 // DefaultPasswordRequirements returns default password requirements
 func DefaultPasswordRequirements() PasswordRequirements {
 	return PasswordRequirements{
-		MinLength:      8,
-		RequireUpper:   true,
-		RequireLower:   true,
-		RequireDigit:   true,
-		RequireSpecial: true,
+		MinLength:          8,
+		MaxLength:          72, // bcrypt's limit, so the default suits either hash algorithm
+		RequireUpper:       true,
+		RequireLower:       true,
+		RequireDigit:       true,
+		RequireSpecial:     true,
+		RejectPersonalInfo: true,
+		RejectBreached:     true,
 	}
 }
 
+// PasswordRequirementsFromConfig builds the requirements from configuration
+func PasswordRequirementsFromConfig(cfg *config.Config) PasswordRequirements {
+	return PasswordRequirements{
+		MinLength:          cfg.Password.MinLength,
+		MaxLength:          cfg.Password.MaxLength,
+		RequireUpper:       cfg.Password.RequireUpper,
+		RequireLower:       cfg.Password.RequireLower,
+		RequireDigit:       cfg.Password.RequireDigit,
+		RequireSpecial:     cfg.Password.RequireSpecial,
+		RejectPersonalInfo: cfg.Password.RejectPersonalInfo,
+		RejectBreached:     cfg.Password.RejectBreached,
+	}
+}
+
+var (
+	passwordRequirements   = DefaultPasswordRequirements()
+	passwordRequirementsMu sync.RWMutex
+)
+
+// SetPasswordRequirements replaces the requirements used by ValidatePassword
+func SetPasswordRequirements(req PasswordRequirements) {
+	passwordRequirementsMu.Lock()
+	defer passwordRequirementsMu.Unlock()
+	passwordRequirements = req
+}
+
+// CurrentPasswordRequirements returns the requirements in effect
+func CurrentPasswordRequirements() PasswordRequirements {
+	passwordRequirementsMu.RLock()
+	defer passwordRequirementsMu.RUnlock()
+	return passwordRequirements
+}
+
+// PasswordContext is personal information a password must not contain
+type PasswordContext struct {
+	Username string
+	Email    string
+	FullName string
+}
+
+// PasswordViolation is one requirement a password failed. Code is stable and
+// names a catalog message; Params fill its placeholders.
+type PasswordViolation struct {
+	Code   string
+	Params map[string]string
+}
+
+// Message returns the violation's text in lang
+func (v PasswordViolation) Message(lang Language) string {
+	return Localize(lang, v.Code, v.Params, "")
+}
+
+func (v PasswordViolation) String() string {
+	return v.Message(English)
+}
+
+// PasswordPolicyError lists every requirement a password failed
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.String()
+	}
+	return "password does not meet requirements: " + strings.Join(messages, "; ")
+}
+
 // ValidatePassword validates password against requirements
 func ValidatePassword(password string) error {
-	req := DefaultPasswordRequirements()
+	return ValidatePasswordForUser(password, PasswordContext{})
+}
 
-	// Check minimum length
+// ValidatePasswordForUser validates password against requirements and the
+// user's personal information, returning a *PasswordPolicyError with every violation
+func ValidatePasswordForUser(password string, ctx PasswordContext) error {
+	if violations := PasswordViolations(password, CurrentPasswordRequirements(), ctx); len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// PasswordViolations returns every requirement the password fails
+func PasswordViolations(password string, req PasswordRequirements, ctx PasswordContext) []PasswordViolation {
+	var violations []PasswordViolation
+	violate := func(code string, params map[string]string) {
+		violations = append(violations, PasswordViolation{Code: code, Params: params})
+	}
+
+	// Check length
 	if len(password) < req.MinLength {
-		return fmt.Errorf("password must be at least %d characters long", req.MinLength)
+		violate("password_too_short", map[string]string{"min": strconv.Itoa(req.MinLength)})
+	}
+	if req.MaxLength > 0 && len(password) > req.MaxLength {
+		violate("password_too_long", map[string]string{"max": strconv.Itoa(req.MaxLength)})
 	}
 
 	var hasUpper, hasLower, hasDigit, hasSpecial bool
@@ -53,51 +153,129 @@ func ValidatePassword(password string) error {
 		}
 	}
 
-	// Validate requirements
-	var errors []string
-
 	if req.RequireUpper && !hasUpper {
-		errors = append(errors, "at least one uppercase letter")
+		violate("password_missing_upper", nil)
 	}
 	if req.RequireLower && !hasLower {
-		errors = append(errors, "at least one lowercase letter")
+		violate("password_missing_lower", nil)
 	}
 	if req.RequireDigit && !hasDigit {
-		errors = append(errors, "at least one number")
+		violate("password_missing_digit", nil)
 	}
 	if req.RequireSpecial && !hasSpecial {
-		errors = append(errors, "at least one special character (!@#$%^&*)")
+		violate("password_missing_special", nil)
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("password must contain: %v", errors)
+	if req.RejectPersonalInfo {
+		if field := containsPersonalInfo(password, ctx); field != "" {
+			violate("password_contains_"+field, nil)
+		}
 	}
 
-	return nil
+	if req.RejectBreached {
+		if count, err := PasswordBreachCount(password); err == nil && count > 0 {
+			violate("password_breached", nil)
+		}
+	}
+
+	return violations
 }
 
-// HashPassword hashes a password using bcrypt with validation
+// containsPersonalInfo returns which personal field the password contains, if any
+func containsPersonalInfo(password string, ctx PasswordContext) string {
+	lower := strings.ToLower(password)
+
+	contains := func(value string) bool {
+		value = strings.ToLower(strings.TrimSpace(value))
+		return len(value) >= 3 && strings.Contains(lower, value)
+	}
+
+	if contains(ctx.Username) {
+		return "username"
+	}
+
+	local, _, _ := strings.Cut(ctx.Email, "@")
+	if contains(local) {
+		return "email"
+	}
+
+	for _, part := range strings.Fields(ctx.FullName) {
+		if contains(part) {
+			return "name"
+		}
+	}
+
+	return ""
+}
+
+// HashPassword hashes a password with the current hasher after validation
 func HashPassword(password string) (string, error) {
 	// Validate password first
 	if err := ValidatePassword(password); err != nil {
 		return "", err
 	}
 
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return HashPasswordUnvalidated(password)
+}
+
+// HashPasswordUnvalidated hashes a password without applying the password policy.
+// Only use it for credentials the service generates or must keep for
+// compatibility; user-chosen passwords go through HashPassword.
+func HashPasswordUnvalidated(password string) (string, error) {
+	hashed, err := CurrentPasswordHasher().Hash(password)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %v", err)
 	}
-	return string(hashedBytes), nil
+	return hashed, nil
+}
+
+// CheckPasswordHash compares a password with its hash. needsRehash is true
+// when the password matches but the hash uses another algorithm or weaker
+// parameters than the current hasher, so the caller should store a new hash.
+func CheckPasswordHash(password, hash string) (match bool, needsRehash bool) {
+	hasher := hasherFor(hash)
+	if hasher == nil {
+		return false, false
+	}
+
+	match, err := hasher.Verify(password, hash)
+	if err != nil || !match {
+		return false, false
+	}
+
+	current := CurrentPasswordHasher()
+	return true, hasher != current || current.NeedsRehash(hash)
 }
 
-// CheckPasswordHash compares a password with its hash
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// PasswordStrength is the result of a password strength evaluation.
+// Level and FeedbackCodes are stable codes, Strength and Feedback their text.
+type PasswordStrength struct {
+	Score         int      `json:"score"`
+	Level         string   `json:"level"`
+	Strength      string   `json:"strength"`
+	FeedbackCodes []string `json:"feedback_codes"`
+	Feedback      []string `json:"feedback"`
+	Valid         bool     `json:"valid"` // Meets the password requirements
+}
+
+// Localize returns a copy of the evaluation with its text in lang
+func (s PasswordStrength) Localize(lang Language) PasswordStrength {
+	s.Strength = Localize(lang, "strength_"+s.Level, nil, "")
+	s.Feedback = make([]string, len(s.FeedbackCodes))
+	for i, code := range s.FeedbackCodes {
+		s.Feedback[i] = Localize(lang, code, nil, "")
+	}
+	return s
 }
 
 // ValidatePasswordStrength returns password strength score (1-5)
 func ValidatePasswordStrength(password string) (int, string) {
+	result := EvaluatePasswordStrength(password)
+	return result.Score, result.Strength
+}
+
+// EvaluatePasswordStrength scores a password and explains how to improve it
+func EvaluatePasswordStrength(password string) PasswordStrength {
 	score := 0
 	feedback := []string{}
 
@@ -105,7 +283,7 @@ func ValidatePasswordStrength(password string) (int, string) {
 	if len(password) >= 8 {
 		score++
 	} else {
-		feedback = append(feedback, "increase length to 8+ characters")
+		feedback = append(feedback, "feedback_length")
 	}
 
 	// Character variety checks
@@ -126,30 +304,32 @@ func ValidatePasswordStrength(password string) (int, string) {
 	if hasLower {
 		score++
 	} else {
-		feedback = append(feedback, "add lowercase letters")
+		feedback = append(feedback, "feedback_lowercase")
 	}
 
 	if hasUpper {
 		score++
 	} else {
-		feedback = append(feedback, "add uppercase letters")
+		feedback = append(feedback, "feedback_uppercase")
 	}
 
 	if hasDigit {
 		score++
 	} else {
-		feedback = append(feedback, "add numbers")
+		feedback = append(feedback, "feedback_digit")
 	}
 
 	if hasSpecial {
 		score++
 	} else {
-		feedback = append(feedback, "add special characters")
+		feedback = append(feedback, "feedback_special")
 	}
 
 	// Additional length bonus
 	if len(password) >= 12 {
 		score++
+	} else if len(password) >= 8 {
+		feedback = append(feedback, "feedback_longer")
 	}
 
 	// Common patterns check
@@ -158,28 +338,80 @@ func ValidatePasswordStrength(password string) (int, string) {
 		"000000", "111111", "password123", "admin123",
 	}
 
-	for _, pattern := range commonPatterns {
-		matched, _ := regexp.MatchString("(?i)"+pattern, password)
-		if matched {
-			score = max(score-2, 0)
-			feedback = append(feedback, "avoid common patterns")
-			break
+	if count, err := PasswordBreachCount(password); err == nil && count > 0 {
+		score = 0
+		feedback = append(feedback, "feedback_breached")
+	} else {
+		for _, pattern := range commonPatterns {
+			matched, _ := regexp.MatchString("(?i)"+pattern, password)
+			if matched {
+				score = max(score-2, 0)
+				feedback = append(feedback, "feedback_common_pattern")
+				break
+			}
 		}
 	}
 
-	strength := "Very Weak"
+	level := "very_weak"
 	switch {
 	case score >= 5:
-		strength = "Very Strong"
+		level = "very_strong"
 	case score >= 4:
-		strength = "Strong"
+		level = "strong"
 	case score >= 3:
-		strength = "Medium"
+		level = "medium"
 	case score >= 2:
-		strength = "Weak"
+		level = "weak"
+	}
+
+	return PasswordStrength{
+		Score:         score,
+		Level:         level,
+		FeedbackCodes: feedback,
+		Valid:         ValidatePassword(password) == nil,
+	}.Localize(English)
+}
+
+// GenerateTemporaryPassword returns a random password that meets the default
+// requirements, avoiding look-alike characters
+func GenerateTemporaryPassword(length int) (string, error) {
+	const (
+		upper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+		lower   = "abcdefghijkmnpqrstuvwxyz"
+		digits  = "23456789"
+		special = "!@#$%^&*"
+	)
+	if length < 8 {
+		length = 8
+	}
+
+	sets := []string{upper, lower, digits, special}
+	all := upper + lower + digits + special
+
+	password := make([]byte, length)
+	for i := range password {
+		set := all
+		if i < len(sets) {
+			set = sets[i] // guarantee one of each class
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %v", err)
+		}
+		password[i] = set[n.Int64()]
+	}
+
+	// Shuffle so the guaranteed classes are not always first
+	for i := len(password) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %v", err)
+		}
+		j := n.Int64()
+		password[i], password[j] = password[j], password[i]
 	}
 
-	return score, strength
+	return string(password), nil
 }
 
 // Helper function for max
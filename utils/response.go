@@ -11,56 +11,45 @@ import (
 	"github.com/google/uuid"
 )
 
-// ValidateImageFile validates uploaded image file
-func ValidateImageFile(file *multipart.FileHeader, maxSize int64) error {
-	// Check file size
-	if file.Size > maxSize {
-		return fmt.Errorf("file too large: max size is %d bytes", maxSize)
-	}
-
-	// Check file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	allowedExts := []string{".jpg", ".jpeg", ".png"}
-
-	isAllowed := false
-	for _, allowedExt := range allowedExts {
-		if ext == allowedExt {
-			isAllowed = true
-			break
+// imageFileTypes are the file types a profile photo may have
+var imageFileTypes = map[string]bool{"jpg": true, "jpeg": true, "png": true, "gif": true, "webp": true}
+
+// ValidateImageFile validates uploaded image file. Of allowedTypes (see
+// ALLOWED_FILE_TYPES), only image types are accepted.
+func ValidateImageFile(file *multipart.FileHeader, maxSize int64, allowedTypes []string) error {
+	var imageTypes []string
+	for _, fileType := range allowedTypes {
+		if imageFileTypes[strings.ToLower(fileType)] {
+			imageTypes = append(imageTypes, fileType)
 		}
 	}
+	return validateFile(file, maxSize, imageTypes)
+}
 
-	if !isAllowed {
-		return fmt.Errorf("invalid file type: only jpg, jpeg, png are allowed")
-	}
-
-	return nil
+// ValidateDocumentFile validates uploaded document file against allowedTypes
+func ValidateDocumentFile(file *multipart.FileHeader, maxSize int64, allowedTypes []string) error {
+	return validateFile(file, maxSize, allowedTypes)
 }
 
-// ValidateDocumentFile validates uploaded document file
-func ValidateDocumentFile(file *multipart.FileHeader, maxSize int64) error {
+// validateFile checks the size and extension of an uploaded file
+func validateFile(file *multipart.FileHeader, maxSize int64, allowedTypes []string) error {
 	// Check file size
 	if file.Size > maxSize {
 		return fmt.Errorf("file too large: max size is %d bytes", maxSize)
 	}
 
 	// Check file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	allowedExts := []string{".pdf", ".doc", ".docx", ".jpg", ".jpeg", ".png"}
-
-	isAllowed := false
-	for _, allowedExt := range allowedExts {
-		if ext == allowedExt {
-			isAllowed = true
-			break
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+	for _, allowed := range allowedTypes {
+		if ext != "" && ext == strings.ToLower(allowed) {
+			return nil
 		}
 	}
 
-	if !isAllowed {
-		return fmt.Errorf("invalid file type: only pdf, doc, docx, jpg, jpeg, png are allowed")
+	if len(allowedTypes) == 0 {
+		return fmt.Errorf("invalid file type: no file types are allowed")
 	}
-
-	return nil
+	return fmt.Errorf("invalid file type: only %s are allowed", strings.Join(allowedTypes, ", "))
 }
 
 // SaveUploadedFile saves uploaded file to specified directory